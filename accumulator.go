@@ -0,0 +1,117 @@
+package gigachat
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+type StreamAccumulator struct {
+	response ChatResponse
+	mu       sync.Mutex
+}
+
+func NewStreamAccumulator() *StreamAccumulator {
+	return &StreamAccumulator{}
+}
+
+func (a *StreamAccumulator) Add(event *ChatResponse) {
+	if event == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if event.Created != 0 {
+		a.response.Created = event.Created
+	}
+	if event.Model != "" {
+		a.response.Model = event.Model
+	}
+	if event.Object != "" {
+		a.response.Object = event.Object
+	}
+	if event.Usage.TotalTokens > 0 {
+		a.response.Usage = event.Usage
+	}
+
+	for _, choice := range event.Choices {
+		if choice.Index < 0 {
+			continue
+		}
+		for len(a.response.Choices) <= choice.Index {
+			a.response.Choices = append(a.response.Choices, ChatChoice{Index: len(a.response.Choices)})
+		}
+
+		merged := &a.response.Choices[choice.Index]
+		mergeDelta(&merged.Message, choice.Delta)
+		if choice.FinishReason != "" {
+			merged.FinishReason = choice.FinishReason
+		}
+	}
+}
+
+func (a *StreamAccumulator) Response() *ChatResponse {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	response := a.response
+	response.Choices = make([]ChatChoice, len(a.response.Choices))
+	for i, choice := range a.response.Choices {
+		if choice.Message.FunctionCall != nil {
+			call := *choice.Message.FunctionCall
+			call.Arguments = append(json.RawMessage(nil), call.Arguments...)
+			choice.Message.FunctionCall = &call
+		}
+		choice.Message.Attachments = append([]string(nil), choice.Message.Attachments...)
+		response.Choices[i] = choice
+	}
+	return &response
+}
+
+func mergeDelta(dst *Message, delta Message) {
+	if delta.Role != "" {
+		dst.Role = delta.Role
+	}
+	if delta.Name != "" {
+		dst.Name = delta.Name
+	}
+	if delta.FunctionsStateID != "" {
+		dst.FunctionsStateID = delta.FunctionsStateID
+	}
+	dst.Content += delta.Content
+	dst.Attachments = append(dst.Attachments, delta.Attachments...)
+
+	if delta.FunctionCall != nil {
+		if dst.FunctionCall == nil {
+			dst.FunctionCall = &FunctionCall{}
+		}
+		if delta.FunctionCall.Name != "" {
+			dst.FunctionCall.Name = delta.FunctionCall.Name
+		}
+		dst.FunctionCall.Arguments = mergeArguments(dst.FunctionCall.Arguments, delta.FunctionCall.Arguments)
+	}
+}
+
+func mergeArguments(dst, delta json.RawMessage) json.RawMessage {
+	if len(delta) == 0 || string(delta) == "null" {
+		return dst
+	}
+	if len(dst) == 0 {
+		return append(json.RawMessage(nil), delta...)
+	}
+
+	var base, update map[string]json.RawMessage
+	if json.Unmarshal(dst, &base) != nil || json.Unmarshal(delta, &update) != nil || base == nil || update == nil {
+		return append(json.RawMessage(nil), delta...)
+	}
+	for key, value := range update {
+		base[key] = value
+	}
+
+	merged, err := json.Marshal(base)
+	if err != nil {
+		return append(json.RawMessage(nil), delta...)
+	}
+	return merged
+}
@@ -0,0 +1,96 @@
+package gigachat
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+)
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+type TLSConfigurer interface {
+	ConfigureTLS(config *tls.Config)
+}
+
+func (tm *TokenManager) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := tm.GetAccessTokenContext(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+type StaticTokenAuthenticator struct {
+	token string
+}
+
+func NewStaticTokenAuthenticator(token string) *StaticTokenAuthenticator {
+	return &StaticTokenAuthenticator{token: token}
+}
+
+func (a *StaticTokenAuthenticator) Authenticate(ctx context.Context, req *http.Request) error {
+	if a.token == "" {
+		return &AuthenticationError{Message: "static access token is empty"}
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+type CertificateAuthenticator struct {
+	certificates []tls.Certificate
+}
+
+func NewCertificateAuthenticator(certificates ...tls.Certificate) *CertificateAuthenticator {
+	return &CertificateAuthenticator{certificates: certificates}
+}
+
+func NewCertificateAuthenticatorFromFiles(certFile, keyFile string) (*CertificateAuthenticator, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, &AuthenticationError{Message: "failed to load client certificate", Err: err}
+	}
+	return NewCertificateAuthenticator(cert), nil
+}
+
+func NewCertificateAuthenticatorFromPEM(certPEM, keyPEM []byte) (*CertificateAuthenticator, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, &AuthenticationError{Message: "failed to parse client certificate", Err: err}
+	}
+	return NewCertificateAuthenticator(cert), nil
+}
+
+func (a *CertificateAuthenticator) Authenticate(ctx context.Context, req *http.Request) error {
+	return nil
+}
+
+func (a *CertificateAuthenticator) ConfigureTLS(config *tls.Config) {
+	config.Certificates = append(config.Certificates, a.certificates...)
+}
+
+func configureClientTLS(client *http.Client, configure func(config *tls.Config)) *http.Client {
+	transport, ok := client.Transport.(*http.Transport)
+	if client.Transport == nil {
+		transport, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok {
+		return client
+	}
+
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	configure(transport.TLSClientConfig)
+
+	configured := *client
+	configured.Transport = transport
+	return &configured
+}
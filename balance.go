@@ -0,0 +1,16 @@
+package gigachat
+
+import "context"
+
+func (c *Client) Balance() (*BalanceResponse, error) {
+	return c.BalanceContext(context.Background())
+}
+
+func (c *Client) BalanceContext(ctx context.Context) (*BalanceResponse, error) {
+	var balanceResp BalanceResponse
+	if err := c.doJSON(ctx, OperationBalance, "GET", "/api/v1/balance", nil, &balanceResp); err != nil {
+		return nil, err
+	}
+
+	return &balanceResp, nil
+}
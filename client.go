@@ -1,13 +1,15 @@
 package gigachat
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"strings"
@@ -15,17 +17,33 @@ import (
 )
 
 type Client struct {
-	tokenManager *TokenManager
-	baseURI      string
-	httpClient   *http.Client
-	defaultModel string
+	auth                    Authenticator
+	baseURI                 string
+	httpClient              *http.Client
+	defaultModel            string
+	retryPolicy             *RetryPolicy
+	streamBufferSize        int
+	streamMaxBufferSize     int
+	streamFirstTokenTimeout time.Duration
+	streamIdleTimeout       time.Duration
+	middleware              []Middleware
+	logger                  *slog.Logger
+	logContent              bool
+	clientID                string
+	sessionID               string
+	rootCAs                 *x509.CertPool
 }
 
-func NewClient(tokenManager *TokenManager, options ...ClientOption) *Client {
+func NewClient(auth Authenticator, options ...ClientOption) *Client {
 	c := &Client{
-		tokenManager: tokenManager,
-		baseURI:      "https://gigachat.devices.sberbank.ru",
-		defaultModel: GigaChat,
+		auth:                    auth,
+		baseURI:                 "https://gigachat.devices.sberbank.ru",
+		defaultModel:            GigaChat,
+		streamBufferSize:        DefaultSSEBufferSize,
+		streamMaxBufferSize:     DefaultSSEMaxBufferSize,
+		streamFirstTokenTimeout: DefaultStreamFirstTokenTimeout,
+		streamIdleTimeout:       DefaultStreamIdleTimeout,
+		logger:                  discardLogger(),
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 			Transport: &http.Transport{
@@ -40,6 +58,11 @@ func NewClient(tokenManager *TokenManager, options ...ClientOption) *Client {
 		opt(c)
 	}
 
+	c.httpClient = withRootCAs(c.httpClient, c.rootCAs)
+	if configurer, ok := auth.(TLSConfigurer); ok {
+		c.httpClient = configureClientTLS(c.httpClient, configurer.ConfigureTLS)
+	}
+
 	return c
 }
 
@@ -71,180 +94,262 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
-func (c *Client) Models() (*ModelsResponse, error) {
-	token, err := c.tokenManager.GetAccessToken()
-	if err != nil {
-		return nil, err
+func WithStreamBufferSize(initial, max int) ClientOption {
+	return func(c *Client) {
+		c.streamBufferSize = initial
+		c.streamMaxBufferSize = max
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequest("GET", c.baseURI+"/api/v1/models", nil)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURI+path, reader)
 	if err != nil {
 		return nil, &GigaChatError{Message: "failed to create request", Err: err}
 	}
+	c.setDefaultHeaders(req)
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	return req, nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, &GigaChatError{Message: "request failed", Err: err}
-	}
-	defer resp.Body.Close()
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return c.doWithClient(c.httpClient, req)
+}
+
+func (c *Client) doWithClient(client *http.Client, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	policy := c.retryPolicy
+	refreshed := false
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := rewindRequest(req)
+		if err != nil {
+			return nil, &GigaChatError{Message: "failed to create request", Err: err}
+		}
+
+		if err := c.auth.Authenticate(ctx, attemptReq); err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(attemptReq)
+		if err != nil {
+			if policy.canRetry(attempt) && ctx.Err() == nil {
+				delay := policy.backoff(attempt)
+				c.logger.WarnContext(ctx, "gigachat request failed, retrying",
+					"path", req.URL.Path, "attempt", attempt, "delay", delay, "error", err)
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, &GigaChatError{Message: "request failed", Err: err}
+				}
+				continue
+			}
+			return nil, &GigaChatError{Message: "request failed", Err: err}
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
 
-	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, &GigaChatError{
-			Message: fmt.Sprintf("API request failed: %s", string(body)),
-			Code:    resp.StatusCode,
+		resp.Body.Close()
+		apiErr := newAPIError(resp, body)
+
+		if invalidator, ok := c.auth.(TokenInvalidator); ok && resp.StatusCode == http.StatusUnauthorized && policy != nil && !refreshed {
+			refreshed = true
+			c.logger.InfoContext(ctx, "gigachat request unauthorized, refreshing credentials",
+				"path", req.URL.Path, "request_id", apiErr.RequestID)
+			invalidator.Invalidate()
+			attempt--
+			continue
 		}
+
+		if policy.canRetry(attempt) && policy.isRetryableStatus(resp.StatusCode) {
+			delay := policy.backoff(attempt)
+			if apiErr.RetryAfter > 0 {
+				delay = apiErr.RetryAfter
+			}
+			c.logger.WarnContext(ctx, "gigachat request failed, retrying",
+				"path", req.URL.Path, "attempt", attempt, "delay", delay,
+				"status", resp.StatusCode, "request_id", apiErr.RequestID)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, &GigaChatError{Message: "request failed", Err: err}
+			}
+			continue
+		}
+
+		return nil, apiErr
 	}
+}
 
-	var modelsResp ModelsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
-		return nil, &GigaChatError{Message: "failed to decode response", Err: err}
+func (c *Client) doJSON(ctx context.Context, operation, method, path string, in, out any) error {
+	req, err := c.newJSONRequest(ctx, method, path, in)
+	if err != nil {
+		return err
 	}
 
-	return &modelsResp, nil
+	return c.doDecode(&Call{Operation: operation, Request: req}, out)
 }
 
-func (c *Client) Chat(messages []Message, options ...ChatOption) (*ChatResponse, error) {
-	if err := validateMessages(messages); err != nil {
-		return nil, err
+func (c *Client) newJSONRequest(ctx context.Context, method, path string, in any) (*http.Request, error) {
+	var jsonData []byte
+	if in != nil {
+		var err error
+		jsonData, err = json.Marshal(in)
+		if err != nil {
+			return nil, &GigaChatError{Message: "failed to marshal request", Err: err}
+		}
 	}
 
-	token, err := c.tokenManager.GetAccessToken()
+	req, err := c.newRequest(ctx, method, path, jsonData)
 	if err != nil {
 		return nil, err
 	}
 
-	chatReq := ChatRequest{
-		Model:    c.defaultModel,
-		Messages: messages,
-		Stream:   false,
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
 
-	for _, opt := range options {
-		opt(&chatReq)
-	}
+	return req, nil
+}
 
-	jsonData, err := json.Marshal(chatReq)
-	if err != nil {
-		return nil, &GigaChatError{Message: "failed to marshal request", Err: err}
-	}
+func (c *Client) doDecode(call *Call, out any) error {
+	return c.execute(call, func(call *Call) error {
+		return c.roundTripDecode(call, out)
+	})
+}
 
-	req, err := http.NewRequest("POST", c.baseURI+"/api/v1/chat/completions", bytes.NewBuffer(jsonData))
+func (c *Client) roundTripDecode(call *Call, out any) error {
+	resp, err := c.do(call.Request)
 	if err != nil {
-		return nil, &GigaChatError{Message: "failed to create request", Err: err}
+		return err
 	}
+	call.Response = resp
+	defer resp.Body.Close()
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, &GigaChatError{Message: "request failed", Err: err}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return &GigaChatError{Message: "failed to decode response", Err: err}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, &GigaChatError{
-			Message: fmt.Sprintf("API request failed: %s", string(body)),
-			Code:    resp.StatusCode,
+	return nil
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
 		}
+		clone.Body = body
 	}
+	return clone, nil
+}
 
-	var chatResp ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return nil, &GigaChatError{Message: "failed to decode response", Err: err}
+func (c *Client) Models() (*ModelsResponse, error) {
+	return c.ModelsContext(context.Background())
+}
+
+func (c *Client) ModelsContext(ctx context.Context) (*ModelsResponse, error) {
+	var modelsResp ModelsResponse
+	if err := c.doJSON(ctx, OperationModels, "GET", "/api/v1/models", nil, &modelsResp); err != nil {
+		return nil, err
 	}
 
-	return &chatResp, nil
+	return &modelsResp, nil
 }
 
-type StreamCallback func(event *ChatResponse, done bool, err error)
+func (c *Client) Chat(messages []Message, options ...ChatOption) (*ChatResponse, error) {
+	return c.ChatContext(context.Background(), messages, options...)
+}
 
-func (c *Client) ChatStream(messages []Message, callback StreamCallback, options ...ChatOption) error {
+func (c *Client) ChatContext(ctx context.Context, messages []Message, options ...ChatOption) (*ChatResponse, error) {
 	if err := validateMessages(messages); err != nil {
-		return err
-	}
-
-	token, err := c.tokenManager.GetAccessToken()
-	if err != nil {
-		return err
+		return nil, err
 	}
 
 	chatReq := ChatRequest{
 		Model:    c.defaultModel,
 		Messages: messages,
-		Stream:   true,
+		Stream:   false,
 	}
 
 	for _, opt := range options {
 		opt(&chatReq)
 	}
 
-	jsonData, err := json.Marshal(chatReq)
+	req, err := c.newJSONRequest(ctx, "POST", "/api/v1/chat/completions", nil)
 	if err != nil {
-		return &GigaChatError{Message: "failed to marshal request", Err: err}
+		return nil, err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	chatReq.call.applyHeaders(req)
+
+	start := time.Now()
+	call := &Call{Operation: OperationChat, Request: req, ChatRequest: &chatReq}
+	err = c.execute(call, func(call *Call) error {
+		if err := setJSONBody(call.Request, call.ChatRequest); err != nil {
+			return err
+		}
 
-	req, err := http.NewRequest("POST", c.baseURI+"/api/v1/chat/completions", bytes.NewBuffer(jsonData))
+		var chatResp ChatResponse
+		if err := c.roundTripDecode(call, &chatResp); err != nil {
+			return err
+		}
+		chatResp.RequestID = responseRequestID(call.Response)
+		call.ChatResponse = &chatResp
+		return nil
+	})
+	chatReq.call.recordMeta(call.Response, err, start)
 	if err != nil {
-		return &GigaChatError{Message: "failed to create request", Err: err}
+		return nil, err
+	}
+	if call.ChatResponse == nil {
+		return nil, &GigaChatError{Message: "middleware returned no chat response"}
 	}
 
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
+	return call.ChatResponse, nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return &GigaChatError{Message: "request failed", Err: err}
-	}
-	defer resp.Body.Close()
+type StreamCallback func(event *ChatResponse, done bool, err error)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return &GigaChatError{
-			Message: fmt.Sprintf("API request failed: %s", string(body)),
-			Code:    resp.StatusCode,
-		}
+func (c *Client) ChatStream(messages []Message, callback StreamCallback, options ...ChatOption) error {
+	return c.ChatStreamContext(context.Background(), messages, callback, options...)
+}
+
+func (c *Client) ChatStreamContext(ctx context.Context, messages []Message, callback StreamCallback, options ...ChatOption) error {
+	stream, err := c.OpenChatStreamContext(ctx, messages, options...)
+	if err != nil {
+		return err
 	}
+	defer stream.Close()
 
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			callback(nil, true, nil)
+			return nil
 		}
-
-		if strings.HasPrefix(line, "data:") {
-			dataPart := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
-			if dataPart == "[DONE]" {
-				callback(nil, true, nil)
-				return nil
-			}
-
-			var event ChatResponse
-			if err := json.Unmarshal([]byte(dataPart), &event); err != nil {
-				callback(nil, false, &GigaChatError{Message: "failed to decode event", Err: err})
+		if err != nil {
+			if stream.err == nil {
+				callback(nil, false, err)
 				continue
 			}
-
-			callback(&event, false, nil)
+			return err
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		return &GigaChatError{Message: "stream reading error", Err: err}
+		callback(event, false, nil)
 	}
-
-	return nil
 }
 
 func (c *Client) GenerateImage(prompt string, options ...ImageOption) (*ChatResponse, error) {
+	return c.GenerateImageContext(context.Background(), prompt, options...)
+}
+
+func (c *Client) GenerateImageContext(ctx context.Context, prompt string, options ...ImageOption) (*ChatResponse, error) {
 	if strings.TrimSpace(prompt) == "" {
 		return nil, &ValidationError{Message: "image prompt cannot be empty"}
 	}
@@ -275,51 +380,53 @@ func (c *Client) GenerateImage(prompt string, options ...ImageOption) (*ChatResp
 		chatOpts = append(chatOpts, WithTemperature(*imgOpts.temperature))
 	}
 
-	return c.Chat(messages, chatOpts...)
+	return c.ChatContext(ctx, messages, chatOpts...)
 }
 
 func (c *Client) DownloadImage(fileID string) (string, error) {
+	return c.DownloadImageContext(context.Background(), fileID)
+}
+
+func (c *Client) DownloadImageContext(ctx context.Context, fileID string) (string, error) {
 	if strings.TrimSpace(fileID) == "" {
 		return "", &ValidationError{Message: "file ID cannot be empty"}
 	}
 
-	token, err := c.tokenManager.GetAccessToken()
+	req, err := c.newRequest(ctx, "GET", "/api/v1/files/"+fileID+"/content", nil)
 	if err != nil {
 		return "", err
 	}
 
-	req, err := http.NewRequest("GET", c.baseURI+"/api/v1/files/"+fileID+"/content", nil)
-	if err != nil {
-		return "", &GigaChatError{Message: "failed to create request", Err: err}
-	}
-
 	req.Header.Set("Accept", "application/jpg")
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", &GigaChatError{Message: "request failed", Err: err}
-	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", &GigaChatError{
-			Message: fmt.Sprintf("API request failed: %s", string(body)),
-			Code:    resp.StatusCode,
+	var imageData []byte
+	err = c.execute(&Call{Operation: OperationDownloadFile, Request: req}, func(call *Call) error {
+		resp, err := c.do(call.Request)
+		if err != nil {
+			return err
 		}
-	}
+		call.Response = resp
+		defer resp.Body.Close()
 
-	imageData, err := io.ReadAll(resp.Body)
+		imageData, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return &GigaChatError{Message: "failed to read image data", Err: err}
+		}
+		return nil
+	})
 	if err != nil {
-		return "", &GigaChatError{Message: "failed to read image data", Err: err}
+		return "", err
 	}
 
 	return base64.StdEncoding.EncodeToString(imageData), nil
 }
 
 func (c *Client) CreateImage(prompt string, options ...ImageOption) (*ImageResult, error) {
-	response, err := c.GenerateImage(prompt, options...)
+	return c.CreateImageContext(context.Background(), prompt, options...)
+}
+
+func (c *Client) CreateImageContext(ctx context.Context, prompt string, options ...ImageOption) (*ImageResult, error) {
+	response, err := c.GenerateImageContext(ctx, prompt, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -334,7 +441,7 @@ func (c *Client) CreateImage(prompt string, options ...ImageOption) (*ImageResul
 		return nil, &GigaChatError{Message: "could not extract image ID from response"}
 	}
 
-	imageContent, err := c.DownloadImage(fileID)
+	imageContent, err := c.DownloadImageContext(ctx, fileID)
 	if err != nil {
 		return nil, err
 	}
@@ -348,6 +455,14 @@ func (c *Client) CreateImage(prompt string, options ...ImageOption) (*ImageResul
 
 type ChatOption func(*ChatRequest)
 
+type callOptions struct {
+	firstTokenTimeout *time.Duration
+	idleTimeout       *time.Duration
+	requestID         string
+	sessionID         string
+	meta              *ResponseMeta
+}
+
 func WithModel(model string) ChatOption {
 	return func(cr *ChatRequest) {
 		cr.Model = model
@@ -390,6 +505,21 @@ func WithFunctionCall(functionCall string) ChatOption {
 	}
 }
 
+func WithFunctions(functions ...Function) ChatOption {
+	return func(cr *ChatRequest) {
+		cr.Functions = append(cr.Functions, functions...)
+		if cr.FunctionCall == nil {
+			cr.FunctionCall = "auto"
+		}
+	}
+}
+
+func WithForcedFunction(name string) ChatOption {
+	return func(cr *ChatRequest) {
+		cr.FunctionCall = FunctionCallChoice{Name: name}
+	}
+}
+
 type imageOptions struct {
 	systemMessage string
 	model         string
@@ -422,10 +552,19 @@ func validateMessages(messages []Message) error {
 	}
 
 	for i, msg := range messages {
-		if msg.Role != "user" && msg.Role != "system" && msg.Role != "assistant" {
+		if msg.Role != "user" && msg.Role != "system" && msg.Role != "assistant" && msg.Role != "function" {
 			return &ValidationError{
-				Message: fmt.Sprintf("invalid role '%s' at index %d. Must be 'user', 'system', or 'assistant'", msg.Role, i),
+				Message: fmt.Sprintf("invalid role '%s' at index %d. Must be 'user', 'system', 'assistant', or 'function'", msg.Role, i),
+			}
+		}
+
+		if msg.Role == "assistant" && msg.FunctionCall != nil {
+			if strings.TrimSpace(msg.FunctionCall.Name) == "" {
+				return &ValidationError{
+					Message: fmt.Sprintf("function call name at index %d must be a non-empty string", i),
+				}
 			}
+			continue
 		}
 
 		if strings.TrimSpace(msg.Content) == "" {
@@ -433,6 +572,34 @@ func validateMessages(messages []Message) error {
 				Message: fmt.Sprintf("message content at index %d must be a non-empty string", i),
 			}
 		}
+
+		if msg.Role == "function" && !json.Valid([]byte(msg.Content)) {
+			return &ValidationError{
+				Message: fmt.Sprintf("function result content at index %d must be valid JSON", i),
+			}
+		}
+
+		if len(msg.Attachments) > 0 {
+			if msg.Role != "user" {
+				return &ValidationError{
+					Message: fmt.Sprintf("attachments at index %d are only allowed in 'user' messages", i),
+				}
+			}
+
+			if len(msg.Attachments) > MaxAttachmentsPerMessage {
+				return &ValidationError{
+					Message: fmt.Sprintf("message at index %d has %d attachments, maximum is %d", i, len(msg.Attachments), MaxAttachmentsPerMessage),
+				}
+			}
+
+			for j, id := range msg.Attachments {
+				if strings.TrimSpace(id) == "" {
+					return &ValidationError{
+						Message: fmt.Sprintf("attachment %d at index %d must be a non-empty file ID", j, i),
+					}
+				}
+			}
+		}
 	}
 
 	return nil
@@ -448,11 +615,15 @@ func extractImageID(content string) string {
 }
 
 func Ask(client *Client, question string, options ...ChatOption) (string, error) {
+	return AskContext(context.Background(), client, question, options...)
+}
+
+func AskContext(ctx context.Context, client *Client, question string, options ...ChatOption) (string, error) {
 	messages := []Message{
 		{Role: "user", Content: question},
 	}
 
-	response, err := client.Chat(messages, options...)
+	response, err := client.ChatContext(ctx, messages, options...)
 	if err != nil {
 		return "", err
 	}
@@ -492,3 +663,23 @@ func ExtractContent(response *ChatResponse) string {
 	}
 	return response.Choices[0].Message.Content
 }
+
+func ExtractFunctionCall(response *ChatResponse) *FunctionCall {
+	if len(response.Choices) == 0 {
+		return nil
+	}
+	return response.Choices[0].Message.FunctionCall
+}
+
+func FunctionResultMessage(name string, result any) (Message, error) {
+	content, err := json.Marshal(result)
+	if err != nil {
+		return Message{}, &ValidationError{Message: fmt.Sprintf("failed to marshal result of function '%s': %v", name, err)}
+	}
+
+	return Message{
+		Role:    "function",
+		Name:    name,
+		Content: string(content),
+	}, nil
+}
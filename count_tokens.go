@@ -0,0 +1,42 @@
+package gigachat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func (c *Client) CountTokens(model string, inputs []string) ([]TokenCount, error) {
+	return c.CountTokensContext(context.Background(), model, inputs)
+}
+
+func (c *Client) CountTokensContext(ctx context.Context, model string, inputs []string) ([]TokenCount, error) {
+	if model == "" {
+		model = c.defaultModel
+	}
+
+	if len(inputs) == 0 {
+		return nil, &ValidationError{Message: "token count input cannot be empty"}
+	}
+
+	for i, text := range inputs {
+		if strings.TrimSpace(text) == "" {
+			return nil, &ValidationError{
+				Message: fmt.Sprintf("token count input at index %d must be a non-empty string", i),
+			}
+		}
+	}
+
+	var counts []TokenCount
+	if err := c.doJSON(ctx, OperationCountTokens, "POST", "/api/v1/tokens/count", TokenCountRequest{Model: model, Input: inputs}, &counts); err != nil {
+		return nil, err
+	}
+
+	if len(counts) != len(inputs) {
+		return nil, &GigaChatError{
+			Message: fmt.Sprintf("expected %d token counts in response, got %d", len(inputs), len(counts)),
+		}
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,125 @@
+package gigachat
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	ScopePersonal = "GIGACHAT_API_PERS"
+	ScopeB2B      = "GIGACHAT_API_B2B"
+	ScopeCorp     = "GIGACHAT_API_CORP"
+)
+
+const (
+	EnvAuthKey            = "GIGACHAT_AUTH_KEY"
+	EnvClientID           = "GIGACHAT_CLIENT_ID"
+	EnvClientSecret       = "GIGACHAT_CLIENT_SECRET"
+	EnvScope              = "GIGACHAT_SCOPE"
+	EnvInsecureSkipVerify = "GIGACHAT_INSECURE_SKIP_VERIFY"
+	EnvCABundleFile       = "GIGACHAT_CA_BUNDLE_FILE"
+)
+
+func GetScopes() []string {
+	return []string{
+		ScopePersonal,
+		ScopeB2B,
+		ScopeCorp,
+	}
+}
+
+func ValidateScope(scope string) error {
+	for _, s := range GetScopes() {
+		if s == scope {
+			return nil
+		}
+	}
+	return &ValidationError{
+		Message: fmt.Sprintf("invalid scope '%s'. Must be one of: %s", scope, strings.Join(GetScopes(), ", ")),
+	}
+}
+
+func EncodeAuthKey(clientID, clientSecret string) string {
+	return base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
+}
+
+func ValidateAuthKey(authKey string) error {
+	if strings.TrimSpace(authKey) == "" {
+		return &ValidationError{Message: "authorization key cannot be empty"}
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(authKey)
+	if err != nil {
+		return &ValidationError{Message: fmt.Sprintf("authorization key is not valid base64: %v", err)}
+	}
+
+	clientID, clientSecret, found := strings.Cut(string(decoded), ":")
+	if !found || clientID == "" || clientSecret == "" {
+		return &ValidationError{Message: "authorization key must decode to 'client_id:client_secret'"}
+	}
+
+	return nil
+}
+
+func NewTokenManagerFromCredentials(clientID, clientSecret string, options ...TokenManagerOption) (*TokenManager, error) {
+	if strings.TrimSpace(clientID) == "" {
+		return nil, &ValidationError{Message: "client ID cannot be empty"}
+	}
+	if strings.TrimSpace(clientSecret) == "" {
+		return nil, &ValidationError{Message: "client secret cannot be empty"}
+	}
+
+	return newValidatedTokenManager(EncodeAuthKey(clientID, clientSecret), options...)
+}
+
+func NewTokenManagerFromEnv(options ...TokenManagerOption) (*TokenManager, error) {
+	authKey := os.Getenv(EnvAuthKey)
+	if authKey == "" {
+		clientID := os.Getenv(EnvClientID)
+		clientSecret := os.Getenv(EnvClientSecret)
+		if clientID == "" || clientSecret == "" {
+			return nil, &ValidationError{
+				Message: fmt.Sprintf("set %s or both %s and %s", EnvAuthKey, EnvClientID, EnvClientSecret),
+			}
+		}
+		authKey = EncodeAuthKey(clientID, clientSecret)
+	}
+
+	envOptions := []TokenManagerOption{}
+	if scope := os.Getenv(EnvScope); scope != "" {
+		envOptions = append(envOptions, WithScope(scope))
+	}
+	if value := os.Getenv(EnvInsecureSkipVerify); value != "" {
+		skip, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, &ValidationError{Message: fmt.Sprintf("invalid %s value '%s'", EnvInsecureSkipVerify, value)}
+		}
+		envOptions = append(envOptions, WithInsecureSkipVerify(skip))
+	}
+	if file := os.Getenv(EnvCABundleFile); file != "" {
+		pool, err := LoadCACertPool(file)
+		if err != nil {
+			return nil, err
+		}
+		envOptions = append(envOptions, WithRootCAs(pool))
+	}
+
+	return newValidatedTokenManager(authKey, append(envOptions, options...)...)
+}
+
+func newValidatedTokenManager(authKey string, options ...TokenManagerOption) (*TokenManager, error) {
+	if err := ValidateAuthKey(authKey); err != nil {
+		return nil, err
+	}
+
+	tm := newTokenManager(authKey, options...)
+	if err := ValidateScope(tm.scope); err != nil {
+		return nil, err
+	}
+	tm.start()
+
+	return tm, nil
+}
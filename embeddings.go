@@ -0,0 +1,114 @@
+package gigachat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const DefaultEmbeddingsBatchSize = 100
+
+type embeddingsOptions struct {
+	model     string
+	batchSize int
+}
+
+type EmbeddingsOption func(*embeddingsOptions)
+
+func WithEmbeddingsModel(model string) EmbeddingsOption {
+	return func(eo *embeddingsOptions) {
+		eo.model = model
+	}
+}
+
+func WithEmbeddingsBatchSize(size int) EmbeddingsOption {
+	return func(eo *embeddingsOptions) {
+		eo.batchSize = size
+	}
+}
+
+func (c *Client) Embeddings(input []string, options ...EmbeddingsOption) (*EmbeddingsResponse, error) {
+	return c.EmbeddingsContext(context.Background(), input, options...)
+}
+
+func (c *Client) EmbeddingsContext(ctx context.Context, input []string, options ...EmbeddingsOption) (*EmbeddingsResponse, error) {
+	embOpts := &embeddingsOptions{
+		model:     Embeddings,
+		batchSize: DefaultEmbeddingsBatchSize,
+	}
+	for _, opt := range options {
+		opt(embOpts)
+	}
+
+	if err := validateEmbeddingsInput(embOpts.model, input); err != nil {
+		return nil, err
+	}
+	if embOpts.batchSize <= 0 {
+		return nil, &ValidationError{Message: "embeddings batch size must be positive"}
+	}
+
+	result := &EmbeddingsResponse{
+		Data: make([]Embedding, 0, len(input)),
+	}
+
+	for start := 0; start < len(input); start += embOpts.batchSize {
+		end := start + embOpts.batchSize
+		if end > len(input) {
+			end = len(input)
+		}
+
+		batch, err := c.embeddingsBatch(ctx, EmbeddingsRequest{
+			Model: embOpts.model,
+			Input: input[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range batch.Data {
+			e.Index += start
+			result.Data = append(result.Data, e)
+		}
+		result.Model = batch.Model
+		result.Object = batch.Object
+	}
+
+	return result, nil
+}
+
+func (c *Client) embeddingsBatch(ctx context.Context, embReq EmbeddingsRequest) (*EmbeddingsResponse, error) {
+	var embResp EmbeddingsResponse
+	if err := c.doJSON(ctx, OperationEmbeddings, "POST", "/api/v1/embeddings", embReq, &embResp); err != nil {
+		return nil, err
+	}
+
+	if len(embResp.Data) != len(embReq.Input) {
+		return nil, &GigaChatError{
+			Message: fmt.Sprintf("expected %d embeddings in response, got %d", len(embReq.Input), len(embResp.Data)),
+		}
+	}
+
+	return &embResp, nil
+}
+
+func validateEmbeddingsInput(model string, input []string) error {
+	if !IsValidEmbeddingModel(model) {
+		return &ValidationError{
+			Message: fmt.Sprintf("invalid embedding model '%s'. Must be one of: %s", model, strings.Join(GetEmbeddingModels(), ", ")),
+		}
+	}
+
+	if len(input) == 0 {
+		return &ValidationError{Message: "embeddings input cannot be empty"}
+	}
+
+	for i, text := range input {
+		if strings.TrimSpace(text) == "" {
+			return &ValidationError{
+				Message: fmt.Sprintf("embeddings input at index %d must be a non-empty string", i),
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,37 @@
 package gigachat
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBadRequest          = errors.New("gigachat: bad request")
+	ErrUnauthorized        = errors.New("gigachat: unauthorized")
+	ErrPaymentRequired     = errors.New("gigachat: payment required")
+	ErrForbidden           = errors.New("gigachat: permission denied")
+	ErrNotFound            = errors.New("gigachat: not found")
+	ErrPayloadTooLarge     = errors.New("gigachat: payload too large")
+	ErrUnprocessableEntity = errors.New("gigachat: unprocessable entity")
+	ErrRateLimited         = errors.New("gigachat: too many requests")
+	ErrServerError         = errors.New("gigachat: server error")
+	ErrMaxStepsExceeded    = errors.New("gigachat: max steps exceeded without a final answer")
+	ErrStreamTimeout       = errors.New("gigachat: stream timeout")
+)
 
 type GigaChatError struct {
-	Message string
-	Code    int
-	Err     error
+	Message    string
+	Code       int
+	APIStatus  int
+	APIMessage string
+	RetryAfter time.Duration
+	RequestID  string
+	Header     http.Header
+	Err        error
 }
 
 func (e *GigaChatError) Error() string {
@@ -19,6 +45,121 @@ func (e *GigaChatError) Unwrap() error {
 	return e.Err
 }
 
+func (e *GigaChatError) Is(target error) bool {
+	sentinel := statusError(e.Code)
+	return sentinel != nil && sentinel == target
+}
+
+func statusError(code int) error {
+	switch {
+	case code == http.StatusBadRequest:
+		return ErrBadRequest
+	case code == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case code == http.StatusPaymentRequired:
+		return ErrPaymentRequired
+	case code == http.StatusForbidden:
+		return ErrForbidden
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusRequestEntityTooLarge:
+		return ErrPayloadTooLarge
+	case code == http.StatusUnprocessableEntity:
+		return ErrUnprocessableEntity
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code >= http.StatusInternalServerError:
+		return ErrServerError
+	}
+	return nil
+}
+
+func newAPIError(resp *http.Response, body []byte) *GigaChatError {
+	apiErr := &GigaChatError{
+		Message: fmt.Sprintf("API request failed: %s", string(body)),
+		Code:    resp.StatusCode,
+	}
+
+	var payload struct {
+		Status  int    `json:"status"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr.APIStatus = payload.Status
+		if apiErr.APIStatus == 0 {
+			apiErr.APIStatus = payload.Code
+		}
+		apiErr.APIMessage = payload.Message
+	}
+	if apiErr.APIMessage == "" {
+		apiErr.APIMessage = strings.TrimSpace(string(body))
+	}
+
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		apiErr.RetryAfter = retryAfter
+	}
+
+	apiErr.RequestID = responseRequestID(resp)
+	apiErr.Header = resp.Header
+
+	return apiErr
+}
+
+type StreamError struct {
+	Status    int
+	Message   string
+	Data      string
+	RequestID string
+}
+
+func (e *StreamError) Error() string {
+	if e.Status != 0 {
+		return fmt.Sprintf("gigachat stream error (status %d): %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("gigachat stream error: %s", e.Message)
+}
+
+func (e *StreamError) Is(target error) bool {
+	sentinel := statusError(e.Status)
+	return sentinel != nil && sentinel == target
+}
+
+func newStreamError(data string) *StreamError {
+	streamErr := &StreamError{Data: data}
+
+	var payload struct {
+		Status  int    `json:"status"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(data), &payload); err == nil {
+		streamErr.Status = payload.Status
+		if streamErr.Status == 0 {
+			streamErr.Status = payload.Code
+		}
+		streamErr.Message = payload.Message
+	}
+	if streamErr.Message == "" {
+		streamErr.Message = strings.TrimSpace(data)
+	}
+
+	return streamErr
+}
+
+type StreamTimeoutError struct {
+	Phase   string
+	Timeout time.Duration
+}
+
+func (e *StreamTimeoutError) Error() string {
+	return fmt.Sprintf("gigachat stream %s timeout after %s", e.Phase, e.Timeout)
+}
+
+func (e *StreamTimeoutError) Is(target error) bool {
+	return target == ErrStreamTimeout
+}
+
 type AuthenticationError struct {
 	Message string
 	Err     error
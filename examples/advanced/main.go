@@ -1,29 +1,19 @@
 package main
 
 import (
-	"encoding/base64"
 	"fmt"
 	"log"
-	"os"
 
 	gigachat "github.com/tigusigalpa/gigachat-go"
 )
 
 func main() {
-	authKey := os.Getenv("GIGACHAT_AUTH_KEY")
-	if authKey == "" {
-		clientID := os.Getenv("GIGACHAT_CLIENT_ID")
-		clientSecret := os.Getenv("GIGACHAT_CLIENT_SECRET")
-		if clientID == "" || clientSecret == "" {
-			log.Fatal("Please set GIGACHAT_AUTH_KEY or GIGACHAT_CLIENT_ID and GIGACHAT_CLIENT_SECRET")
-		}
-		authKey = base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
-	}
-
-	tokenManager := gigachat.NewTokenManager(
-		authKey,
-		gigachat.WithScope("GIGACHAT_API_PERS"),
+	tokenManager, err := gigachat.NewTokenManagerFromEnv(
+		gigachat.WithScope(gigachat.ScopePersonal),
 	)
+	if err != nil {
+		log.Fatalf("Failed to configure credentials: %v", err)
+	}
 
 	client := gigachat.NewClient(
 		tokenManager,
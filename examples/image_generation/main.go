@@ -10,17 +10,10 @@ import (
 )
 
 func main() {
-	authKey := os.Getenv("GIGACHAT_AUTH_KEY")
-	if authKey == "" {
-		clientID := os.Getenv("GIGACHAT_CLIENT_ID")
-		clientSecret := os.Getenv("GIGACHAT_CLIENT_SECRET")
-		if clientID == "" || clientSecret == "" {
-			log.Fatal("Please set GIGACHAT_AUTH_KEY or GIGACHAT_CLIENT_ID and GIGACHAT_CLIENT_SECRET")
-		}
-		authKey = base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
+	tokenManager, err := gigachat.NewTokenManagerFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure credentials: %v", err)
 	}
-
-	tokenManager := gigachat.NewTokenManager(authKey)
 	client := gigachat.NewClient(tokenManager)
 
 	fmt.Println("Generating image...")
@@ -0,0 +1,185 @@
+package gigachat
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FilePurposeGeneral = "general"
+
+	MaxAttachmentsPerMessage = 10
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func (c *Client) UploadFile(filename string, content io.Reader, purpose string) (*FileInfo, error) {
+	return c.UploadFileContext(context.Background(), filename, content, purpose)
+}
+
+func (c *Client) UploadFileContext(ctx context.Context, filename string, content io.Reader, purpose string) (*FileInfo, error) {
+	if strings.TrimSpace(filename) == "" {
+		return nil, &ValidationError{Message: "file name cannot be empty"}
+	}
+	if content == nil {
+		return nil, &ValidationError{Message: "file content cannot be nil"}
+	}
+	if purpose == "" {
+		purpose = FilePurposeGeneral
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(filepath.Base(filename))))
+	header.Set("Content-Type", detectContentType(filename))
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, &GigaChatError{Message: "failed to create multipart body", Err: err}
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, &GigaChatError{Message: "failed to read file content", Err: err}
+	}
+	if err := writer.WriteField("purpose", purpose); err != nil {
+		return nil, &GigaChatError{Message: "failed to create multipart body", Err: err}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, &GigaChatError{Message: "failed to create multipart body", Err: err}
+	}
+
+	req, err := c.newRequest(ctx, "POST", "/api/v1/files", body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	var fileInfo FileInfo
+	if err := c.doDecode(&Call{Operation: OperationUploadFile, Request: req}, &fileInfo); err != nil {
+		return nil, err
+	}
+
+	return &fileInfo, nil
+}
+
+func (c *Client) UploadFileFromPath(path, purpose string) (*FileInfo, error) {
+	return c.UploadFileFromPathContext(context.Background(), path, purpose)
+}
+
+func (c *Client) UploadFileFromPathContext(ctx context.Context, path, purpose string) (*FileInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("failed to open file '%s': %v", path, err)}
+	}
+	defer file.Close()
+
+	return c.UploadFileContext(ctx, filepath.Base(path), file, purpose)
+}
+
+func (c *Client) UserMessageWithFiles(content string, paths ...string) (Message, error) {
+	return c.UserMessageWithFilesContext(context.Background(), content, paths...)
+}
+
+func (c *Client) UserMessageWithFilesContext(ctx context.Context, content string, paths ...string) (Message, error) {
+	if len(paths) > MaxAttachmentsPerMessage {
+		return Message{}, &ValidationError{
+			Message: fmt.Sprintf("too many attachments: %d, maximum is %d", len(paths), MaxAttachmentsPerMessage),
+		}
+	}
+
+	msg := Message{
+		Role:    "user",
+		Content: content,
+	}
+
+	for _, path := range paths {
+		fileInfo, err := c.UploadFileFromPathContext(ctx, path, FilePurposeGeneral)
+		if err != nil {
+			c.deleteUploadedFiles(ctx, msg.Attachments)
+			return Message{}, err
+		}
+		msg.Attachments = append(msg.Attachments, fileInfo.ID)
+	}
+
+	return msg, nil
+}
+
+func (c *Client) deleteUploadedFiles(ctx context.Context, fileIDs []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, fileID := range fileIDs {
+		if _, err := c.DeleteFileContext(ctx, fileID); err != nil {
+			c.logger.WarnContext(ctx, "gigachat failed to delete uploaded file", "file_id", fileID, "error", err)
+		}
+	}
+}
+
+func (c *Client) ListFiles() (*FilesResponse, error) {
+	return c.ListFilesContext(context.Background())
+}
+
+func (c *Client) ListFilesContext(ctx context.Context) (*FilesResponse, error) {
+	var filesResp FilesResponse
+	if err := c.doJSON(ctx, OperationListFiles, "GET", "/api/v1/files", nil, &filesResp); err != nil {
+		return nil, err
+	}
+
+	return &filesResp, nil
+}
+
+func (c *Client) GetFile(fileID string) (*FileInfo, error) {
+	return c.GetFileContext(context.Background(), fileID)
+}
+
+func (c *Client) GetFileContext(ctx context.Context, fileID string) (*FileInfo, error) {
+	if strings.TrimSpace(fileID) == "" {
+		return nil, &ValidationError{Message: "file ID cannot be empty"}
+	}
+
+	var fileInfo FileInfo
+	if err := c.doJSON(ctx, OperationGetFile, "GET", "/api/v1/files/"+url.PathEscape(fileID), nil, &fileInfo); err != nil {
+		return nil, err
+	}
+
+	return &fileInfo, nil
+}
+
+func (c *Client) DeleteFile(fileID string) (*DeleteFileResponse, error) {
+	return c.DeleteFileContext(context.Background(), fileID)
+}
+
+func (c *Client) DeleteFileContext(ctx context.Context, fileID string) (*DeleteFileResponse, error) {
+	if strings.TrimSpace(fileID) == "" {
+		return nil, &ValidationError{Message: "file ID cannot be empty"}
+	}
+
+	var deleteResp DeleteFileResponse
+	if err := c.doJSON(ctx, OperationDeleteFile, "POST", "/api/v1/files/"+url.PathEscape(fileID)+"/delete", nil, &deleteResp); err != nil {
+		return nil, err
+	}
+
+	return &deleteResp, nil
+}
+
+func detectContentType(filename string) string {
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(filename)))
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return contentType
+}
@@ -0,0 +1,69 @@
+package gigachat
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+	HeaderSessionID = "X-Session-ID"
+	HeaderClientID  = "X-Client-ID"
+)
+
+func WithClientID(clientID string) ClientOption {
+	return func(c *Client) {
+		c.clientID = clientID
+	}
+}
+
+func WithDefaultSessionID(sessionID string) ClientOption {
+	return func(c *Client) {
+		c.sessionID = sessionID
+	}
+}
+
+func WithRequestID(requestID string) ChatOption {
+	return func(cr *ChatRequest) {
+		cr.call.requestID = requestID
+	}
+}
+
+func WithSessionID(sessionID string) ChatOption {
+	return func(cr *ChatRequest) {
+		cr.call.sessionID = sessionID
+	}
+}
+
+func (c *Client) setDefaultHeaders(req *http.Request) {
+	req.Header.Set(HeaderRequestID, uuid.New().String())
+	if c.clientID != "" {
+		req.Header.Set(HeaderClientID, c.clientID)
+	}
+	if c.sessionID != "" {
+		req.Header.Set(HeaderSessionID, c.sessionID)
+	}
+}
+
+func (o callOptions) applyHeaders(req *http.Request) {
+	if o.requestID != "" {
+		req.Header.Set(HeaderRequestID, o.requestID)
+	}
+	if o.sessionID != "" {
+		req.Header.Set(HeaderSessionID, o.sessionID)
+	}
+}
+
+func responseRequestID(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	if requestID := resp.Header.Get(HeaderRequestID); requestID != "" {
+		return requestID
+	}
+	if resp.Request != nil {
+		return resp.Request.Header.Get(HeaderRequestID)
+	}
+	return ""
+}
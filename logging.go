@@ -0,0 +1,144 @@
+package gigachat
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const redactedValue = "REDACTED"
+
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+func discardLogger() *slog.Logger {
+	return slog.New(discardHandler{})
+}
+
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			logger = discardLogger()
+		}
+		c.logger = logger
+	}
+}
+
+func WithLogContent(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.logContent = enabled
+	}
+}
+
+func WithTokenManagerLogger(logger *slog.Logger) TokenManagerOption {
+	return func(tm *TokenManager) {
+		if logger == nil {
+			logger = discardLogger()
+		}
+		tm.logger = logger
+	}
+}
+
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+func (c *Client) logCall(call *Call, start time.Time, err error) {
+	ctx := call.Request.Context()
+	if !c.logger.Enabled(ctx, slog.LevelError) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", call.Operation),
+		slog.String("method", call.Request.Method),
+		slog.String("path", call.Request.URL.Path),
+		slog.Duration("latency", time.Since(start)),
+	}
+	if call.Response != nil {
+		attrs = append(attrs,
+			slog.Int("status", call.Response.StatusCode),
+			slog.String("request_id", responseRequestID(call.Response)),
+		)
+	}
+	if call.ChatRequest != nil {
+		attrs = append(attrs, c.chatRequestAttrs(call.ChatRequest)...)
+	}
+	if call.ChatResponse != nil {
+		attrs = append(attrs, c.chatResponseAttrs(call.ChatResponse)...)
+	}
+	attrs = append(attrs, slog.Any("request_headers", redactHeaders(call.Request.Header)))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		c.logger.LogAttrs(ctx, slog.LevelError, "gigachat request failed", attrs...)
+		return
+	}
+
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "gigachat request completed", attrs...)
+
+	if call.Operation == OperationChatStream {
+		call.OnStreamEnd(func(streamErr error) {
+			c.logStreamEnd(call, start, streamErr)
+		})
+	}
+}
+
+func (c *Client) logStreamEnd(call *Call, start time.Time, err error) {
+	ctx := call.Request.Context()
+	attrs := []slog.Attr{
+		slog.String("operation", call.Operation),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("request_id", responseRequestID(call.Response)),
+	}
+	if call.ChatResponse != nil {
+		attrs = append(attrs, c.chatResponseAttrs(call.ChatResponse)...)
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "gigachat stream ended with error", attrs...)
+		return
+	}
+
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "gigachat stream completed", attrs...)
+}
+
+func (c *Client) chatRequestAttrs(req *ChatRequest) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("model", req.Model),
+		slog.Int("message_count", len(req.Messages)),
+	}
+	if c.logContent {
+		attrs = append(attrs, slog.Any("messages", req.Messages))
+	}
+	return attrs
+}
+
+func (c *Client) chatResponseAttrs(resp *ChatResponse) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.Int("prompt_tokens", resp.Usage.PromptTokens),
+		slog.Int("completion_tokens", resp.Usage.CompletionTokens),
+		slog.Int("total_tokens", resp.Usage.TotalTokens),
+	}
+	if len(resp.Choices) > 0 {
+		attrs = append(attrs, slog.String("finish_reason", resp.Choices[0].FinishReason))
+		if c.logContent {
+			attrs = append(attrs, slog.String("content", resp.Choices[0].Message.Content))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,120 @@
+package gigachat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RateLimit struct {
+	LimitRequests     int
+	RemainingRequests int
+	LimitTokens       int
+	RemainingTokens   int
+	Reset             time.Duration
+}
+
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	RequestID  string
+	Latency    time.Duration
+	RateLimit  *RateLimit
+}
+
+func WithResponseMeta(meta *ResponseMeta) ChatOption {
+	return func(cr *ChatRequest) {
+		cr.call.meta = meta
+	}
+}
+
+func (c *Client) ChatWithMeta(messages []Message, options ...ChatOption) (*ChatResponse, *ResponseMeta, error) {
+	return c.ChatWithMetaContext(context.Background(), messages, options...)
+}
+
+func (c *Client) ChatWithMetaContext(ctx context.Context, messages []Message, options ...ChatOption) (*ChatResponse, *ResponseMeta, error) {
+	var meta ResponseMeta
+	options = append(append([]ChatOption(nil), options...), WithResponseMeta(&meta))
+	response, err := c.ChatContext(ctx, messages, options...)
+	if meta.Header == nil {
+		return response, nil, err
+	}
+	return response, &meta, err
+}
+
+func (o callOptions) recordMeta(resp *http.Response, err error, start time.Time) {
+	if o.meta == nil {
+		return
+	}
+
+	meta := ResponseMeta{Latency: time.Since(start)}
+	var apiErr *GigaChatError
+	switch {
+	case resp != nil:
+		meta.StatusCode = resp.StatusCode
+		meta.Header = resp.Header
+		meta.RequestID = responseRequestID(resp)
+	case errors.As(err, &apiErr) && apiErr.Header != nil:
+		meta.StatusCode = apiErr.Code
+		meta.Header = apiErr.Header
+		meta.RequestID = apiErr.RequestID
+	default:
+		return
+	}
+	meta.RateLimit = parseRateLimit(meta.Header)
+
+	*o.meta = meta
+}
+
+func parseRateLimit(header http.Header) *RateLimit {
+	limit := &RateLimit{}
+	found := false
+
+	parse := func(target *int, names ...string) {
+		for _, name := range names {
+			if value, err := strconv.Atoi(strings.TrimSpace(header.Get(name))); err == nil {
+				*target = value
+				found = true
+				return
+			}
+		}
+	}
+
+	parse(&limit.LimitRequests, "X-RateLimit-Limit-Requests", "X-RateLimit-Limit")
+	parse(&limit.RemainingRequests, "X-RateLimit-Remaining-Requests", "X-RateLimit-Remaining")
+	parse(&limit.LimitTokens, "X-RateLimit-Limit-Tokens")
+	parse(&limit.RemainingTokens, "X-RateLimit-Remaining-Tokens")
+
+	for _, name := range []string{"X-RateLimit-Reset-Requests", "X-RateLimit-Reset"} {
+		if reset, ok := parseRateLimitReset(header.Get(name)); ok {
+			limit.Reset = reset
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return limit
+}
+
+func parseRateLimitReset(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		if seconds > 1000000000 {
+			return max(time.Until(time.Unix(int64(seconds), 0)), 0), true
+		}
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return duration, true
+	}
+	return 0, false
+}
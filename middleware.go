@@ -0,0 +1,80 @@
+package gigachat
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	OperationChat         = "chat"
+	OperationChatStream   = "chat_stream"
+	OperationModels       = "models"
+	OperationEmbeddings   = "embeddings"
+	OperationCountTokens  = "count_tokens"
+	OperationBalance      = "balance"
+	OperationUploadFile   = "upload_file"
+	OperationListFiles    = "list_files"
+	OperationGetFile      = "get_file"
+	OperationDeleteFile   = "delete_file"
+	OperationDownloadFile = "download_file"
+)
+
+type Call struct {
+	Operation    string
+	Request      *http.Request
+	Response     *http.Response
+	ChatRequest  *ChatRequest
+	ChatResponse *ChatResponse
+
+	streamEndHooks []func(err error)
+}
+
+func (c *Call) OnStreamEnd(hook func(err error)) {
+	c.streamEndHooks = append(c.streamEndHooks, hook)
+}
+
+func (c *Call) endStream(resp *ChatResponse, err error) {
+	c.ChatResponse = resp
+	for _, hook := range c.streamEndHooks {
+		hook(err)
+	}
+}
+
+type Handler func(call *Call) error
+
+type Middleware func(next Handler) Handler
+
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+func (c *Client) execute(call *Call, final Handler) error {
+	handler := final
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+
+	start := time.Now()
+	err := handler(call)
+	c.logCall(call, start, err)
+	return err
+}
+
+func setJSONBody(req *http.Request, in any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return &GigaChatError{Message: "failed to marshal request", Err: err}
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return nil
+}
@@ -1,5 +1,7 @@
 package gigachat
 
+import "encoding/json"
+
 const (
 	GigaChat2    = "GigaChat-2"
 	GigaChat2Pro = "GigaChat-2-Pro"
@@ -13,20 +15,66 @@ const (
 )
 
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role             string        `json:"role"`
+	Content          string        `json:"content"`
+	Name             string        `json:"name,omitempty"`
+	FunctionCall     *FunctionCall `json:"function_call,omitempty"`
+	FunctionsStateID string        `json:"functions_state_id,omitempty"`
+	Attachments      []string      `json:"attachments,omitempty"`
+}
+
+type FunctionCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type Function struct {
+	Name             string              `json:"name"`
+	Description      string              `json:"description,omitempty"`
+	Parameters       FunctionParameters  `json:"parameters"`
+	FewShotExamples  []FewShotExample    `json:"few_shot_examples,omitempty"`
+	ReturnParameters *FunctionParameters `json:"return_parameters,omitempty"`
+}
+
+type FunctionParameters struct {
+	Type        string                      `json:"type"`
+	Description string                      `json:"description,omitempty"`
+	Properties  map[string]FunctionProperty `json:"properties"`
+	Required    []string                    `json:"required,omitempty"`
+}
+
+type FunctionProperty struct {
+	Type        string                      `json:"type"`
+	Description string                      `json:"description,omitempty"`
+	Enum        []string                    `json:"enum,omitempty"`
+	Items       *FunctionProperty           `json:"items,omitempty"`
+	Properties  map[string]FunctionProperty `json:"properties,omitempty"`
+	Required    []string                    `json:"required,omitempty"`
+}
+
+type FewShotExample struct {
+	Request string         `json:"request"`
+	Params  map[string]any `json:"params"`
+}
+
+type FunctionCallChoice struct {
+	Name             string         `json:"name"`
+	PartialArguments map[string]any `json:"partial_arguments,omitempty"`
 }
 
 type ChatRequest struct {
-	Model             string    `json:"model"`
-	Messages          []Message `json:"messages"`
-	Temperature       *float64  `json:"temperature,omitempty"`
-	TopP              *float64  `json:"top_p,omitempty"`
-	MaxTokens         *int      `json:"max_tokens,omitempty"`
-	RepetitionPenalty *float64  `json:"repetition_penalty,omitempty"`
-	UpdateInterval    *int      `json:"update_interval,omitempty"`
-	Stream            bool      `json:"stream"`
-	FunctionCall      string    `json:"function_call,omitempty"`
+	Model             string     `json:"model"`
+	Messages          []Message  `json:"messages"`
+	Temperature       *float64   `json:"temperature,omitempty"`
+	TopP              *float64   `json:"top_p,omitempty"`
+	MaxTokens         *int       `json:"max_tokens,omitempty"`
+	RepetitionPenalty *float64   `json:"repetition_penalty,omitempty"`
+	UpdateInterval    *int       `json:"update_interval,omitempty"`
+	Stream            bool       `json:"stream"`
+	Functions         []Function `json:"functions,omitempty"`
+	FunctionCall      any        `json:"function_call,omitempty"`
+
+	call callOptions
 }
 
 type ChatChoice struct {
@@ -48,6 +96,8 @@ type ChatResponse struct {
 	Model   string       `json:"model"`
 	Usage   Usage        `json:"usage"`
 	Object  string       `json:"object"`
+
+	RequestID string `json:"-"`
 }
 
 type Model struct {
@@ -61,6 +111,93 @@ type ModelsResponse struct {
 	Object string  `json:"object"`
 }
 
+type EmbeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type EmbeddingUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+}
+
+type Embedding struct {
+	Object    string         `json:"object"`
+	Embedding []float64      `json:"embedding"`
+	Index     int            `json:"index"`
+	Usage     EmbeddingUsage `json:"usage"`
+}
+
+type EmbeddingsResponse struct {
+	Data   []Embedding `json:"data"`
+	Model  string      `json:"model"`
+	Object string      `json:"object"`
+}
+
+func (r *EmbeddingsResponse) Vectors() [][]float64 {
+	vectors := make([][]float64, len(r.Data))
+	for i, e := range r.Data {
+		vectors[i] = e.Embedding
+	}
+	return vectors
+}
+
+func (r *EmbeddingsResponse) TotalTokens() int {
+	total := 0
+	for _, e := range r.Data {
+		total += e.Usage.PromptTokens
+	}
+	return total
+}
+
+type TokenCountRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type TokenCount struct {
+	Object     string `json:"object"`
+	Tokens     int    `json:"tokens"`
+	Characters int    `json:"characters"`
+}
+
+type BalanceEntry struct {
+	Usage string `json:"usage"`
+	Value int64  `json:"value"`
+}
+
+type BalanceResponse struct {
+	Balance []BalanceEntry `json:"balance"`
+}
+
+func (r *BalanceResponse) Remaining(usage string) (int64, bool) {
+	for _, entry := range r.Balance {
+		if entry.Usage == usage {
+			return entry.Value, true
+		}
+	}
+	return 0, false
+}
+
+type FileInfo struct {
+	ID           string `json:"id"`
+	Object       string `json:"object"`
+	Bytes        int64  `json:"bytes"`
+	CreatedAt    int64  `json:"created_at"`
+	Filename     string `json:"filename"`
+	Purpose      string `json:"purpose"`
+	AccessPolicy string `json:"access_policy,omitempty"`
+}
+
+type FilesResponse struct {
+	Data []FileInfo `json:"data"`
+}
+
+type DeleteFileResponse struct {
+	ID           string `json:"id"`
+	Deleted      bool   `json:"deleted"`
+	AccessPolicy string `json:"access_policy,omitempty"`
+}
+
 type TokenResponse struct {
 	AccessToken string `json:"access_token"`
 	ExpiresAt   int64  `json:"expires_at"`
@@ -0,0 +1,18 @@
+module github.com/tigusigalpa/gigachat-go/otelgigachat
+
+go 1.21
+
+require (
+	github.com/tigusigalpa/gigachat-go v0.0.0
+	go.opentelemetry.io/otel v1.29.0
+	go.opentelemetry.io/otel/metric v1.29.0
+	go.opentelemetry.io/otel/trace v1.29.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+)
+
+replace github.com/tigusigalpa/gigachat-go => ../
@@ -0,0 +1,349 @@
+package otelgigachat
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	gigachat "github.com/tigusigalpa/gigachat-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ScopeName = "github.com/tigusigalpa/gigachat-go/otelgigachat"
+	System    = "gigachat"
+)
+
+const (
+	AttrSystem                = attribute.Key("gen_ai.system")
+	AttrOperationName         = attribute.Key("gen_ai.operation.name")
+	AttrRequestModel          = attribute.Key("gen_ai.request.model")
+	AttrRequestTemperature    = attribute.Key("gen_ai.request.temperature")
+	AttrRequestTopP           = attribute.Key("gen_ai.request.top_p")
+	AttrRequestMaxTokens      = attribute.Key("gen_ai.request.max_tokens")
+	AttrResponseModel         = attribute.Key("gen_ai.response.model")
+	AttrResponseFinishReasons = attribute.Key("gen_ai.response.finish_reasons")
+	AttrUsageInputTokens      = attribute.Key("gen_ai.usage.input_tokens")
+	AttrUsageOutputTokens     = attribute.Key("gen_ai.usage.output_tokens")
+	AttrTokenType             = attribute.Key("gen_ai.token.type")
+	AttrServerAddress         = attribute.Key("server.address")
+	AttrServerPort            = attribute.Key("server.port")
+	AttrHTTPStatusCode        = attribute.Key("http.response.status_code")
+	AttrErrorType             = attribute.Key("error.type")
+	AttrRequestID             = attribute.Key("gigachat.request_id")
+)
+
+const (
+	MetricOperationDuration = "gen_ai.client.operation.duration"
+	MetricTokenUsage        = "gen_ai.client.token.usage"
+	MetricErrors            = "gigachat.client.errors"
+	MetricTokenRefresh      = "gigachat.token.refresh.duration"
+)
+
+const (
+	operationTokenRefresh = "token_refresh"
+	errorTypeOther        = "_OTHER"
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+type Option func(*config)
+
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+type instruments struct {
+	tracer       trace.Tracer
+	duration     metric.Float64Histogram
+	tokenUsage   metric.Int64Histogram
+	errors       metric.Int64Counter
+	refreshTimes metric.Float64Histogram
+}
+
+func newInstruments(options []Option) *instruments {
+	cfg := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	inst := &instruments{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+
+	var err error
+	inst.duration, err = meter.Float64Histogram(MetricOperationDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of GigaChat API operations"),
+		metric.WithExplicitBucketBoundaries(0.01, 0.02, 0.04, 0.08, 0.16, 0.32, 0.64, 1.28, 2.56, 5.12, 10.24, 20.48, 40.96, 81.92))
+	if err != nil {
+		otel.Handle(err)
+	}
+	inst.tokenUsage, err = meter.Int64Histogram(MetricTokenUsage,
+		metric.WithUnit("{token}"),
+		metric.WithDescription("Number of input and output tokens used"),
+		metric.WithExplicitBucketBoundaries(1, 4, 16, 64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304))
+	if err != nil {
+		otel.Handle(err)
+	}
+	inst.errors, err = meter.Int64Counter(MetricErrors,
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Number of failed GigaChat API operations"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	inst.refreshTimes, err = meter.Float64Histogram(MetricTokenRefresh,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of OAuth access token refreshes"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return inst
+}
+
+func NewMiddleware(options ...Option) gigachat.Middleware {
+	inst := newInstruments(options)
+
+	return func(next gigachat.Handler) gigachat.Handler {
+		return func(call *gigachat.Call) error {
+			return inst.handle(next, call)
+		}
+	}
+}
+
+func NewTokenRefreshMiddleware(options ...Option) gigachat.TokenRefreshMiddleware {
+	inst := newInstruments(options)
+
+	return func(next gigachat.TokenRefreshHandler) gigachat.TokenRefreshHandler {
+		return func(ctx context.Context) (string, time.Time, error) {
+			attrs := []attribute.KeyValue{
+				AttrSystem.String(System),
+				AttrOperationName.String(operationTokenRefresh),
+			}
+
+			ctx, span := inst.tracer.Start(ctx, "gigachat token refresh",
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...))
+			defer span.End()
+
+			start := time.Now()
+			token, expiresAt, err := next(ctx)
+
+			if err != nil {
+				attrs = append(attrs, AttrErrorType.String(errorType(err)))
+				recordSpanError(span, err)
+				inst.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+			}
+			inst.refreshTimes.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+
+			return token, expiresAt, err
+		}
+	}
+}
+
+func (inst *instruments) handle(next gigachat.Handler, call *gigachat.Call) error {
+	operation := genAIOperation(call.Operation)
+	model := ""
+	if call.ChatRequest != nil {
+		model = call.ChatRequest.Model
+	}
+
+	attrs := []attribute.KeyValue{
+		AttrSystem.String(System),
+		AttrOperationName.String(operation),
+	}
+	if model != "" {
+		attrs = append(attrs, AttrRequestModel.String(model))
+	}
+	if host := call.Request.URL.Hostname(); host != "" {
+		attrs = append(attrs, AttrServerAddress.String(host))
+		if port, err := strconv.Atoi(call.Request.URL.Port()); err == nil {
+			attrs = append(attrs, AttrServerPort.Int(port))
+		}
+	}
+
+	spanName := operation
+	if model != "" {
+		spanName += " " + model
+	}
+
+	ctx, span := inst.tracer.Start(call.Request.Context(), spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(requestAttributes(call.ChatRequest)...))
+	call.Request = call.Request.WithContext(ctx)
+
+	start := time.Now()
+	err := next(call)
+
+	if call.Response != nil {
+		span.SetAttributes(
+			AttrHTTPStatusCode.Int(call.Response.StatusCode),
+			AttrRequestID.String(requestID(call)),
+		)
+	}
+
+	if err != nil || call.Operation != gigachat.OperationChatStream {
+		inst.finish(ctx, span, call, attrs, start, err)
+		return err
+	}
+
+	call.OnStreamEnd(func(streamErr error) {
+		if errors.Is(streamErr, context.Canceled) {
+			streamErr = nil
+		}
+		inst.finish(ctx, span, call, attrs, start, streamErr)
+	})
+	return nil
+}
+
+func (inst *instruments) finish(ctx context.Context, span trace.Span, call *gigachat.Call, attrs []attribute.KeyValue, start time.Time, err error) {
+	defer span.End()
+
+	if resp := call.ChatResponse; resp != nil {
+		span.SetAttributes(responseAttributes(resp)...)
+		if resp.Model != "" {
+			attrs = append(attrs, AttrResponseModel.String(resp.Model))
+		}
+		inst.recordTokens(ctx, attrs, resp.Usage)
+	}
+
+	if err != nil {
+		attrs = append(attrs, AttrErrorType.String(errorType(err)))
+		if code := statusCode(err); code != 0 {
+			attrs = append(attrs, AttrHTTPStatusCode.Int(code))
+			span.SetAttributes(AttrHTTPStatusCode.Int(code))
+		}
+		recordSpanError(span, err)
+		inst.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+
+	inst.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+}
+
+func (inst *instruments) recordTokens(ctx context.Context, attrs []attribute.KeyValue, usage gigachat.Usage) {
+	if usage.PromptTokens > 0 {
+		inst.tokenUsage.Record(ctx, int64(usage.PromptTokens),
+			metric.WithAttributes(append(attrs, AttrTokenType.String("input"))...))
+	}
+	if usage.CompletionTokens > 0 {
+		inst.tokenUsage.Record(ctx, int64(usage.CompletionTokens),
+			metric.WithAttributes(append(attrs, AttrTokenType.String("output"))...))
+	}
+}
+
+func requestAttributes(req *gigachat.ChatRequest) []attribute.KeyValue {
+	if req == nil {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+	if req.Temperature != nil {
+		attrs = append(attrs, AttrRequestTemperature.Float64(*req.Temperature))
+	}
+	if req.TopP != nil {
+		attrs = append(attrs, AttrRequestTopP.Float64(*req.TopP))
+	}
+	if req.MaxTokens != nil {
+		attrs = append(attrs, AttrRequestMaxTokens.Int(*req.MaxTokens))
+	}
+	return attrs
+}
+
+func responseAttributes(resp *gigachat.ChatResponse) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		AttrUsageInputTokens.Int(resp.Usage.PromptTokens),
+		AttrUsageOutputTokens.Int(resp.Usage.CompletionTokens),
+	}
+	if resp.Model != "" {
+		attrs = append(attrs, AttrResponseModel.String(resp.Model))
+	}
+
+	reasons := make([]string, 0, len(resp.Choices))
+	for _, choice := range resp.Choices {
+		if choice.FinishReason != "" {
+			reasons = append(reasons, choice.FinishReason)
+		}
+	}
+	if len(reasons) > 0 {
+		attrs = append(attrs, AttrResponseFinishReasons.StringSlice(reasons))
+	}
+	return attrs
+}
+
+func requestID(call *gigachat.Call) string {
+	if id := call.Response.Header.Get(gigachat.HeaderRequestID); id != "" {
+		return id
+	}
+	return call.Request.Header.Get(gigachat.HeaderRequestID)
+}
+
+func genAIOperation(operation string) string {
+	switch operation {
+	case gigachat.OperationChat, gigachat.OperationChatStream:
+		return "chat"
+	default:
+		return operation
+	}
+}
+
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.SetAttributes(AttrErrorType.String(errorType(err)))
+}
+
+func statusCode(err error) int {
+	var apiErr *gigachat.GigaChatError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
+func errorType(err error) string {
+	if code := statusCode(err); code != 0 {
+		return strconv.Itoa(code)
+	}
+	if errors.Is(err, gigachat.ErrStreamTimeout) {
+		return "timeout"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	var streamErr *gigachat.StreamError
+	if errors.As(err, &streamErr) {
+		return "stream_error"
+	}
+	var authErr *gigachat.AuthenticationError
+	if errors.As(err, &authErr) {
+		return "authentication_error"
+	}
+	var validationErr *gigachat.ValidationError
+	if errors.As(err, &validationErr) {
+		return "validation_error"
+	}
+	return errorTypeOther
+}
@@ -0,0 +1,122 @@
+package gigachat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const DefaultMaxSteps = 10
+
+type FunctionHandler func(ctx context.Context, arguments json.RawMessage) (any, error)
+
+type Runner struct {
+	client    *Client
+	functions []Function
+	handlers  map[string]FunctionHandler
+	maxSteps  int
+	options   []ChatOption
+}
+
+type RunnerOption func(*Runner)
+
+func WithMaxSteps(steps int) RunnerOption {
+	return func(r *Runner) {
+		r.maxSteps = steps
+	}
+}
+
+func WithRunnerChatOptions(options ...ChatOption) RunnerOption {
+	return func(r *Runner) {
+		r.options = append(r.options, options...)
+	}
+}
+
+func NewRunner(client *Client, options ...RunnerOption) *Runner {
+	r := &Runner{
+		client:   client,
+		handlers: make(map[string]FunctionHandler),
+		maxSteps: DefaultMaxSteps,
+	}
+
+	for _, opt := range options {
+		opt(r)
+	}
+
+	return r
+}
+
+func (r *Runner) Register(function Function, handler FunctionHandler) {
+	if _, exists := r.handlers[function.Name]; exists {
+		for i := range r.functions {
+			if r.functions[i].Name == function.Name {
+				r.functions[i] = function
+			}
+		}
+	} else {
+		r.functions = append(r.functions, function)
+	}
+	r.handlers[function.Name] = handler
+}
+
+type RunResult struct {
+	Response *ChatResponse
+	Messages []Message
+	Steps    int
+}
+
+func (r *Runner) Run(messages []Message) (*RunResult, error) {
+	return r.RunContext(context.Background(), messages)
+}
+
+func (r *Runner) RunContext(ctx context.Context, messages []Message) (*RunResult, error) {
+	if r.maxSteps <= 0 {
+		return nil, &ValidationError{Message: "runner max steps must be positive"}
+	}
+
+	result := &RunResult{
+		Messages: append([]Message(nil), messages...),
+	}
+
+	options := append([]ChatOption{}, r.options...)
+	if len(r.functions) > 0 {
+		options = append(options, WithFunctions(r.functions...))
+	}
+
+	for result.Steps < r.maxSteps {
+		result.Steps++
+
+		response, err := r.client.ChatContext(ctx, result.Messages, options...)
+		if err != nil {
+			return result, err
+		}
+		result.Response = response
+
+		call := ExtractFunctionCall(response)
+		if call == nil {
+			if len(response.Choices) > 0 {
+				result.Messages = append(result.Messages, response.Choices[0].Message)
+			}
+			return result, nil
+		}
+		result.Messages = append(result.Messages, response.Choices[0].Message)
+
+		handler, ok := r.handlers[call.Name]
+		if !ok {
+			return result, &GigaChatError{Message: fmt.Sprintf("model called unregistered function '%s'", call.Name)}
+		}
+
+		output, err := handler(ctx, call.Arguments)
+		if err != nil {
+			return result, &GigaChatError{Message: fmt.Sprintf("function '%s' failed", call.Name), Err: err}
+		}
+
+		functionMessage, err := FunctionResultMessage(call.Name, output)
+		if err != nil {
+			return result, err
+		}
+		result.Messages = append(result.Messages, functionMessage)
+	}
+
+	return result, ErrMaxStepsExceeded
+}
@@ -0,0 +1,291 @@
+package gigachat
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func ParametersFromStruct(v any) (FunctionParameters, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return FunctionParameters{}, &ValidationError{Message: fmt.Sprintf("function parameters must be a struct, got %v", t)}
+	}
+
+	prop, err := schemaForType(t, map[reflect.Type]bool{})
+	if err != nil {
+		return FunctionParameters{}, err
+	}
+
+	return FunctionParameters{
+		Type:       prop.Type,
+		Properties: prop.Properties,
+		Required:   prop.Required,
+	}, nil
+}
+
+func NewFunction(name, description string, parameters any) (Function, error) {
+	params, err := ParametersFromStruct(parameters)
+	if err != nil {
+		return Function{}, err
+	}
+
+	return Function{
+		Name:        name,
+		Description: description,
+		Parameters:  params,
+	}, nil
+}
+
+func DecodeArguments(arguments json.RawMessage, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &ValidationError{Message: "function arguments destination must be a non-nil pointer"}
+	}
+
+	if len(bytes.TrimSpace(arguments)) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+
+	t := rv.Type().Elem()
+	if t.Kind() == reflect.Struct {
+		schema, err := schemaForType(t, map[reflect.Type]bool{})
+		if err != nil {
+			return err
+		}
+
+		var raw any
+		if err := json.Unmarshal(arguments, &raw); err != nil {
+			return &ValidationError{Message: fmt.Sprintf("function arguments are not valid JSON: %v", err)}
+		}
+		if err := validateArgument(schema, raw, ""); err != nil {
+			return err
+		}
+	}
+
+	if err := json.Unmarshal(arguments, dst); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("failed to decode function arguments: %v", err)}
+	}
+
+	return nil
+}
+
+func TypedHandler[T any](handler func(ctx context.Context, args T) (any, error)) FunctionHandler {
+	return func(ctx context.Context, arguments json.RawMessage) (any, error) {
+		var args T
+		if err := DecodeArguments(arguments, &args); err != nil {
+			return nil, err
+		}
+		return handler(ctx, args)
+	}
+}
+
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) (FunctionProperty, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if isStringEncoded(t) {
+		return FunctionProperty{Type: "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return FunctionProperty{Type: "string"}, nil
+	case reflect.Bool:
+		return FunctionProperty{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FunctionProperty{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return FunctionProperty{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := schemaForType(t.Elem(), visiting)
+		if err != nil {
+			return FunctionProperty{}, err
+		}
+		return FunctionProperty{Type: "array", Items: &items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return FunctionProperty{}, &ValidationError{Message: fmt.Sprintf("unsupported map key type %v in function parameters", t.Key())}
+		}
+		return FunctionProperty{Type: "object"}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return FunctionProperty{}, &ValidationError{Message: fmt.Sprintf("recursive type %v is not supported in function parameters", t)}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		prop := FunctionProperty{
+			Type:       "object",
+			Properties: map[string]FunctionProperty{},
+		}
+		if err := addStructFields(&prop, t, visiting); err != nil {
+			return FunctionProperty{}, err
+		}
+		return prop, nil
+	default:
+		return FunctionProperty{}, &ValidationError{Message: fmt.Sprintf("unsupported type %v in function parameters", t)}
+	}
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func isStringEncoded(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return true
+	}
+	pt := reflect.PointerTo(t)
+	return pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType)
+}
+
+func addStructFields(prop *FunctionProperty, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addStructFields(prop, embedded, visiting); err != nil {
+					return err
+				}
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldProp, err := schemaForType(field.Type, visiting)
+		if err != nil {
+			return err
+		}
+
+		fieldProp.Description = field.Tag.Get("description")
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values := strings.Split(enum, ",")
+			for j := range values {
+				values[j] = strings.TrimSpace(values[j])
+			}
+			if fieldProp.Type == "array" && fieldProp.Items != nil {
+				fieldProp.Items.Enum = values
+			} else {
+				fieldProp.Enum = values
+			}
+		}
+
+		prop.Properties[name] = fieldProp
+		if field.Tag.Get("required") == "true" {
+			prop.Required = append(prop.Required, name)
+		}
+	}
+
+	return nil
+}
+
+func validateArgument(schema FunctionProperty, value any, path string) error {
+	if value == nil {
+		return nil
+	}
+
+	subject := path
+	if subject == "" {
+		subject = "arguments"
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return &ValidationError{Message: fmt.Sprintf("%s must be an object", subject)}
+		}
+		for _, name := range schema.Required {
+			if v, ok := obj[name]; !ok || v == nil {
+				return &ValidationError{Message: fmt.Sprintf("missing required argument '%s'", joinArgumentPath(path, name))}
+			}
+		}
+		for name, v := range obj {
+			if fieldSchema, ok := schema.Properties[name]; ok {
+				if err := validateArgument(fieldSchema, v, joinArgumentPath(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return &ValidationError{Message: fmt.Sprintf("%s must be an array", subject)}
+		}
+		if schema.Items != nil {
+			for i, v := range arr {
+				if err := validateArgument(*schema.Items, v, fmt.Sprintf("%s[%d]", subject, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return &ValidationError{Message: fmt.Sprintf("%s must be a string", subject)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &ValidationError{Message: fmt.Sprintf("%s must be a boolean", subject)}
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			return &ValidationError{Message: fmt.Sprintf("%s must be an integer", subject)}
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return &ValidationError{Message: fmt.Sprintf("%s must be a number", subject)}
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		actual := fmt.Sprint(value)
+		for _, allowed := range schema.Enum {
+			if allowed == actual {
+				return nil
+			}
+		}
+		return &ValidationError{
+			Message: fmt.Sprintf("%s must be one of [%s], got '%s'", subject, strings.Join(schema.Enum, ", "), actual),
+		}
+	}
+
+	return nil
+}
+
+func joinArgumentPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,164 @@
+package gigachat
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultSSEBufferSize    = 64 * 1024
+	DefaultSSEMaxBufferSize = 4 * 1024 * 1024
+)
+
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Retry int
+}
+
+type SSEDecoder struct {
+	reader        io.Reader
+	scanner       *bufio.Scanner
+	bufferSize    int
+	maxBufferSize int
+	lastEventID   string
+	started       bool
+}
+
+type SSEDecoderOption func(*SSEDecoder)
+
+func WithSSEBufferSize(initial, max int) SSEDecoderOption {
+	return func(d *SSEDecoder) {
+		d.bufferSize = initial
+		d.maxBufferSize = max
+	}
+}
+
+func NewSSEDecoder(r io.Reader, options ...SSEDecoderOption) *SSEDecoder {
+	d := &SSEDecoder{
+		reader:        r,
+		bufferSize:    DefaultSSEBufferSize,
+		maxBufferSize: DefaultSSEMaxBufferSize,
+	}
+
+	for _, opt := range options {
+		opt(d)
+	}
+
+	if d.bufferSize <= 0 {
+		d.bufferSize = DefaultSSEBufferSize
+	}
+	if d.maxBufferSize < d.bufferSize {
+		d.maxBufferSize = d.bufferSize
+	}
+
+	d.scanner = bufio.NewScanner(r)
+	d.scanner.Buffer(make([]byte, 0, d.bufferSize), d.maxBufferSize)
+	d.scanner.Split(scanSSELines)
+
+	return d
+}
+
+func (d *SSEDecoder) Next() (*SSEEvent, error) {
+	var (
+		data      strings.Builder
+		eventType string
+		retry     int
+		hasData   bool
+	)
+
+	dispatch := func() *SSEEvent {
+		event := &SSEEvent{
+			ID:    d.lastEventID,
+			Event: eventType,
+			Data:  strings.TrimSuffix(data.String(), "\n"),
+			Retry: retry,
+		}
+		if event.Event == "" {
+			event.Event = "message"
+		}
+		return event
+	}
+
+	for d.scanner.Scan() {
+		line := d.scanner.Text()
+		if !d.started {
+			d.started = true
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+
+		if line == "" {
+			if hasData {
+				return dispatch(), nil
+			}
+			data.Reset()
+			eventType = ""
+			retry = 0
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, found := strings.Cut(line, ":")
+		if found {
+			value = strings.TrimPrefix(value, " ")
+		}
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				d.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				retry = ms
+			}
+		}
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+func scanSSELines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		return 0, nil, nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
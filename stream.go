@@ -0,0 +1,207 @@
+package gigachat
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Stream struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	resp      *http.Response
+	decoder   *SSEDecoder
+	acc       *StreamAccumulator
+	watchdog  *streamWatchdog
+	call      *Call
+	logger    *slog.Logger
+	logData   bool
+	err       error
+	closeOnce sync.Once
+}
+
+func (c *Client) OpenChatStream(messages []Message, options ...ChatOption) (*Stream, error) {
+	return c.OpenChatStreamContext(context.Background(), messages, options...)
+}
+
+func (c *Client) OpenChatStreamContext(ctx context.Context, messages []Message, options ...ChatOption) (*Stream, error) {
+	if err := validateMessages(messages); err != nil {
+		return nil, err
+	}
+
+	chatReq := ChatRequest{
+		Model:    c.defaultModel,
+		Messages: messages,
+		Stream:   true,
+	}
+
+	for _, opt := range options {
+		opt(&chatReq)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	firstToken, idle := c.streamTimeouts(chatReq.call)
+	watchdog := newStreamWatchdog(cancel, firstToken, idle)
+
+	req, err := c.newRequest(ctx, "POST", "/api/v1/chat/completions", nil)
+	if err != nil {
+		watchdog.stop()
+		cancel()
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Content-Type", "application/json")
+	chatReq.call.applyHeaders(req)
+
+	start := time.Now()
+	call := &Call{Operation: OperationChatStream, Request: req, ChatRequest: &chatReq}
+	err = c.execute(call, func(call *Call) error {
+		if err := setJSONBody(call.Request, call.ChatRequest); err != nil {
+			return err
+		}
+
+		resp, err := c.doWithClient(c.streamHTTPClient(firstToken, idle), call.Request)
+		if err != nil {
+			if timeoutErr := watchdog.timeoutErr(); timeoutErr != nil {
+				return timeoutErr
+			}
+			return err
+		}
+		call.Response = resp
+		return nil
+	})
+	if err == nil && call.Response == nil {
+		err = &GigaChatError{Message: "middleware returned no stream response"}
+	}
+	chatReq.call.recordMeta(call.Response, err, start)
+	if err != nil {
+		watchdog.stop()
+		cancel()
+		if call.Response != nil {
+			call.Response.Body.Close()
+		}
+		return nil, err
+	}
+	watchdog.connected()
+
+	resp := call.Response
+	body := &watchedReader{r: resp.Body, watchdog: watchdog}
+
+	return &Stream{
+		ctx:      ctx,
+		cancel:   cancel,
+		resp:     resp,
+		decoder:  NewSSEDecoder(body, WithSSEBufferSize(c.streamBufferSize, c.streamMaxBufferSize)),
+		acc:      NewStreamAccumulator(),
+		watchdog: watchdog,
+		call:     call,
+		logger:   c.logger,
+		logData:  c.logContent,
+	}, nil
+}
+
+func (s *Stream) Recv() (*ChatResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	for {
+		sseEvent, err := s.decoder.Next()
+		if err == io.EOF {
+			return nil, s.finish(io.EOF)
+		}
+		if err != nil {
+			if timeoutErr := s.watchdog.timeoutErr(); timeoutErr != nil {
+				return nil, s.finish(timeoutErr)
+			}
+			if ctxErr := s.ctx.Err(); ctxErr != nil {
+				return nil, s.finish(&GigaChatError{Message: "stream canceled", Err: ctxErr})
+			}
+			return nil, s.finish(&GigaChatError{Message: "stream reading error", Err: err})
+		}
+
+		if sseEvent.Event == "error" {
+			s.logger.WarnContext(s.ctx, "gigachat stream error event", "data", sseEvent.Data)
+			streamErr := newStreamError(sseEvent.Data)
+			streamErr.RequestID = s.RequestID()
+			return nil, s.finish(streamErr)
+		}
+
+		dataPart := strings.TrimSpace(sseEvent.Data)
+		if dataPart == "" {
+			continue
+		}
+		if dataPart == "[DONE]" {
+			return nil, s.finish(io.EOF)
+		}
+
+		var event ChatResponse
+		if err := json.Unmarshal([]byte(dataPart), &event); err != nil {
+			s.logDecodeError(dataPart, err)
+			return nil, &GigaChatError{Message: "failed to decode event", Err: err}
+		}
+
+		s.watchdog.tokenReceived()
+		s.acc.Add(&event)
+		event.RequestID = s.RequestID()
+		return &event, nil
+	}
+}
+
+func (s *Stream) Header() http.Header {
+	return s.resp.Header
+}
+
+func (s *Stream) RequestID() string {
+	return responseRequestID(s.resp)
+}
+
+func (s *Stream) Response() *ChatResponse {
+	response := s.acc.Response()
+	response.RequestID = s.RequestID()
+	return response
+}
+
+func (s *Stream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.watchdog.stop()
+		s.cancel()
+		err = s.resp.Body.Close()
+		s.call.endStream(s.Response(), context.Canceled)
+	})
+	return err
+}
+
+func (s *Stream) finish(err error) error {
+	s.err = err
+	s.closeOnce.Do(func() {
+		s.watchdog.stop()
+		s.cancel()
+		s.resp.Body.Close()
+
+		endErr := err
+		if endErr == io.EOF {
+			endErr = nil
+		}
+		s.call.endStream(s.Response(), endErr)
+	})
+	return err
+}
+
+func (s *Stream) logDecodeError(data string, err error) {
+	attrs := []slog.Attr{
+		slog.String("request_id", responseRequestID(s.resp)),
+		slog.String("error", err.Error()),
+	}
+	if s.logData {
+		attrs = append(attrs, slog.String("data", data))
+	}
+	s.logger.LogAttrs(s.ctx, slog.LevelWarn, "gigachat stream event decode failed", attrs...)
+}
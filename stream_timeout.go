@@ -0,0 +1,159 @@
+package gigachat
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultStreamFirstTokenTimeout = 60 * time.Second
+	DefaultStreamIdleTimeout       = 30 * time.Second
+)
+
+func WithStreamTimeouts(firstToken, idle time.Duration) ClientOption {
+	return func(c *Client) {
+		c.streamFirstTokenTimeout = firstToken
+		c.streamIdleTimeout = idle
+	}
+}
+
+func WithFirstTokenTimeout(timeout time.Duration) ChatOption {
+	return func(cr *ChatRequest) {
+		cr.call.firstTokenTimeout = &timeout
+	}
+}
+
+func WithStreamIdleTimeout(timeout time.Duration) ChatOption {
+	return func(cr *ChatRequest) {
+		cr.call.idleTimeout = &timeout
+	}
+}
+
+func (c *Client) streamTimeouts(call callOptions) (time.Duration, time.Duration) {
+	firstToken, idle := c.streamFirstTokenTimeout, c.streamIdleTimeout
+	if call.firstTokenTimeout != nil {
+		firstToken = *call.firstTokenTimeout
+	}
+	if call.idleTimeout != nil {
+		idle = *call.idleTimeout
+	}
+	return firstToken, idle
+}
+
+func (c *Client) streamHTTPClient(firstToken, idle time.Duration) *http.Client {
+	if (firstToken <= 0 && idle <= 0) || c.httpClient.Timeout == 0 {
+		return c.httpClient
+	}
+
+	client := *c.httpClient
+	client.Timeout = 0
+	return &client
+}
+
+type streamWatchdog struct {
+	mu         sync.Mutex
+	cancel     context.CancelFunc
+	firstToken time.Duration
+	idle       time.Duration
+	firstTimer *time.Timer
+	idleTimer  *time.Timer
+	err        error
+}
+
+func newStreamWatchdog(cancel context.CancelFunc, firstToken, idle time.Duration) *streamWatchdog {
+	w := &streamWatchdog{
+		cancel:     cancel,
+		firstToken: firstToken,
+		idle:       idle,
+	}
+
+	if firstToken > 0 {
+		w.firstTimer = time.AfterFunc(firstToken, func() {
+			w.fire(&StreamTimeoutError{Phase: "first token", Timeout: firstToken})
+		})
+	}
+
+	return w
+}
+
+func (w *streamWatchdog) connected() {
+	if w.firstToken <= 0 {
+		w.startIdle()
+	}
+}
+
+func (w *streamWatchdog) startIdle() {
+	if w.idle <= 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err == nil && w.idleTimer == nil {
+		w.idleTimer = time.AfterFunc(w.idle, func() {
+			w.fire(&StreamTimeoutError{Phase: "idle", Timeout: w.idle})
+		})
+	}
+}
+
+func (w *streamWatchdog) activity() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.idleTimer != nil && w.err == nil {
+		w.idleTimer.Reset(w.idle)
+	}
+}
+
+func (w *streamWatchdog) tokenReceived() {
+	if w.firstTimer != nil {
+		w.firstTimer.Stop()
+	}
+	w.startIdle()
+}
+
+func (w *streamWatchdog) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.firstTimer != nil {
+		w.firstTimer.Stop()
+	}
+	if w.idleTimer != nil {
+		w.idleTimer.Stop()
+	}
+}
+
+func (w *streamWatchdog) fire(err error) {
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.mu.Unlock()
+
+	w.cancel()
+}
+
+func (w *streamWatchdog) timeoutErr() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+type watchedReader struct {
+	r        io.Reader
+	watchdog *streamWatchdog
+}
+
+func (r *watchedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.watchdog.activity()
+	}
+	return n, err
+}
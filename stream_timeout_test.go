@@ -0,0 +1,61 @@
+package gigachat
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testStreamChunk = "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"x\"}}]}\n\n"
+
+func newTimeoutTestClient(t *testing.T, firstChunkDelay time.Duration, firstToken, idle time.Duration) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-time.After(firstChunkDelay):
+		case <-r.Context().Done():
+			return
+		}
+
+		io.WriteString(w, testStreamChunk)
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	return NewClient(
+		NewStaticTokenAuthenticator("token"),
+		WithBaseURI(srv.URL),
+		WithStreamTimeouts(firstToken, idle),
+	)
+}
+
+func TestStreamSlowFirstTokenIgnoresIdleTimeout(t *testing.T) {
+	client := newTimeoutTestClient(t, 400*time.Millisecond, 2*time.Second, 200*time.Millisecond)
+
+	err := client.ChatStream([]Message{{Role: "user", Content: "hi"}}, func(*ChatResponse, bool, error) {})
+	if err != nil {
+		t.Fatalf("expected slow first token to be governed by the first-token timeout only, got %v", err)
+	}
+}
+
+func TestStreamFirstTokenTimeout(t *testing.T) {
+	client := newTimeoutTestClient(t, time.Second, 200*time.Millisecond, 2*time.Second)
+
+	err := client.ChatStream([]Message{{Role: "user", Content: "hi"}}, func(*ChatResponse, bool, error) {})
+
+	var timeoutErr *StreamTimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Phase != "first token" {
+		t.Fatalf("expected first token timeout, got %v", err)
+	}
+	if !errors.Is(err, ErrStreamTimeout) {
+		t.Fatalf("expected error to match ErrStreamTimeout, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package gigachat
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+func WithRootCAs(pool *x509.CertPool) TokenManagerOption {
+	return func(tm *TokenManager) {
+		tm.rootCAs = pool
+	}
+}
+
+func WithClientRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) {
+		c.rootCAs = pool
+	}
+}
+
+func LoadCACertPool(files ...string) (*x509.CertPool, error) {
+	pemData := make([][]byte, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, &ValidationError{Message: fmt.Sprintf("failed to read CA bundle '%s': %v", file, err)}
+		}
+		pemData = append(pemData, data)
+	}
+	return NewCACertPool(pemData...)
+}
+
+func NewCACertPool(pemData ...[]byte) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	for _, data := range pemData {
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, &ValidationError{Message: "no valid PEM certificates found in CA bundle"}
+		}
+	}
+
+	return pool, nil
+}
+
+func withRootCAs(client *http.Client, pool *x509.CertPool) *http.Client {
+	if pool == nil {
+		return client
+	}
+	return configureClientTLS(client, func(config *tls.Config) {
+		config.RootCAs = pool
+	})
+}
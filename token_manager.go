@@ -2,10 +2,13 @@ package gigachat
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"sync"
@@ -15,20 +18,38 @@ import (
 )
 
 type TokenManager struct {
-	authKey     string
-	scope       string
-	oauthURI    string
-	client      *http.Client
-	accessToken string
-	expiresAt   time.Time
-	mu          sync.RWMutex
+	authKey      string
+	scope        string
+	oauthURI     string
+	client       *http.Client
+	accessToken  string
+	expiresAt    time.Time
+	invalidToken string
+	store        TokenStore
+	logger       *slog.Logger
+	rootCAs      *x509.CertPool
+	middleware   []TokenRefreshMiddleware
+	mu           sync.RWMutex
+
+	refreshAhead   time.Duration
+	onRefreshError func(error)
+	stopRefresh    context.CancelFunc
+	refreshDone    chan struct{}
+	stopOnce       sync.Once
 }
 
 func NewTokenManager(authKey string, options ...TokenManagerOption) *TokenManager {
+	tm := newTokenManager(authKey, options...)
+	tm.start()
+	return tm
+}
+
+func newTokenManager(authKey string, options ...TokenManagerOption) *TokenManager {
 	tm := &TokenManager{
 		authKey:  authKey,
-		scope:    "GIGACHAT_API_PERS",
+		scope:    ScopePersonal,
 		oauthURI: "https://ngw.devices.sberbank.ru:9443",
+		logger:   discardLogger(),
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 			Transport: &http.Transport{
@@ -42,10 +63,17 @@ func NewTokenManager(authKey string, options ...TokenManagerOption) *TokenManage
 	for _, opt := range options {
 		opt(tm)
 	}
+	tm.client = withRootCAs(tm.client, tm.rootCAs)
 
 	return tm
 }
 
+func (tm *TokenManager) start() {
+	if tm.refreshAhead > 0 {
+		tm.startBackgroundRefresh()
+	}
+}
+
 type TokenManagerOption func(*TokenManager)
 
 func WithScope(scope string) TokenManagerOption {
@@ -74,7 +102,27 @@ func WithTokenManagerHTTPClient(client *http.Client) TokenManagerOption {
 	}
 }
 
+func WithTokenStore(store TokenStore) TokenManagerOption {
+	return func(tm *TokenManager) {
+		tm.store = store
+	}
+}
+
+type TokenRefreshHandler func(ctx context.Context) (token string, expiresAt time.Time, err error)
+
+type TokenRefreshMiddleware func(next TokenRefreshHandler) TokenRefreshHandler
+
+func WithTokenRefreshMiddleware(middleware ...TokenRefreshMiddleware) TokenManagerOption {
+	return func(tm *TokenManager) {
+		tm.middleware = append(tm.middleware, middleware...)
+	}
+}
+
 func (tm *TokenManager) GetAccessToken() (string, error) {
+	return tm.GetAccessTokenContext(context.Background())
+}
+
+func (tm *TokenManager) GetAccessTokenContext(ctx context.Context) (string, error) {
 	tm.mu.RLock()
 	if tm.accessToken != "" && time.Now().Add(30*time.Second).Before(tm.expiresAt) {
 		token := tm.accessToken
@@ -83,10 +131,21 @@ func (tm *TokenManager) GetAccessToken() (string, error) {
 	}
 	tm.mu.RUnlock()
 
-	return tm.refreshToken()
+	return tm.refreshToken(ctx)
 }
 
-func (tm *TokenManager) refreshToken() (string, error) {
+func (tm *TokenManager) Invalidate() {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.invalidToken = tm.accessToken
+	tm.accessToken = ""
+	tm.expiresAt = time.Time{}
+
+	tm.logger.Info("gigachat access token invalidated")
+}
+
+func (tm *TokenManager) refreshToken(ctx context.Context) (string, error) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
@@ -94,14 +153,67 @@ func (tm *TokenManager) refreshToken() (string, error) {
 		return tm.accessToken, nil
 	}
 
+	if tm.store != nil {
+		token, expiresAt, err := tm.store.Get(ctx)
+		if err == nil && token != "" && token != tm.invalidToken && time.Now().Add(30*time.Second).Before(expiresAt) {
+			tm.accessToken = token
+			tm.expiresAt = expiresAt
+			tm.logger.DebugContext(ctx, "gigachat access token loaded from store", "expires_at", expiresAt)
+			return tm.accessToken, nil
+		}
+	}
+
+	token, expiresAt, err := tm.fetchToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tm.accessToken = token
+	tm.expiresAt = expiresAt
+	tm.invalidToken = ""
+
+	tm.saveToStore(ctx, token, expiresAt)
+
+	return tm.accessToken, nil
+}
+
+func (tm *TokenManager) saveToStore(ctx context.Context, token string, expiresAt time.Time) {
+	if tm.store == nil {
+		return
+	}
+	if err := tm.store.Set(ctx, token, expiresAt); err != nil {
+		tm.logger.WarnContext(ctx, "gigachat token store write failed", "error", err)
+	}
+}
+
+func (tm *TokenManager) fetchToken(ctx context.Context) (string, time.Time, error) {
+	handler := TokenRefreshHandler(tm.requestToken)
+	for i := len(tm.middleware) - 1; i >= 0; i-- {
+		handler = tm.middleware[i](handler)
+	}
+
+	start := time.Now()
+	token, expiresAt, err := handler(ctx)
+	if err != nil {
+		tm.logger.ErrorContext(ctx, "gigachat token refresh failed",
+			"scope", tm.scope, "latency", time.Since(start), "error", err)
+		return "", time.Time{}, err
+	}
+
+	tm.logger.InfoContext(ctx, "gigachat access token refreshed",
+		"scope", tm.scope, "latency", time.Since(start), "expires_at", expiresAt)
+	return token, expiresAt, nil
+}
+
+func (tm *TokenManager) requestToken(ctx context.Context) (string, time.Time, error) {
 	rqUID := uuid.New().String()
 
 	formData := url.Values{}
 	formData.Set("scope", tm.scope)
 
-	req, err := http.NewRequest("POST", tm.oauthURI+"/api/v2/oauth", bytes.NewBufferString(formData.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", tm.oauthURI+"/api/v2/oauth", bytes.NewBufferString(formData.Encode()))
 	if err != nil {
-		return "", &AuthenticationError{Message: "failed to create request", Err: err}
+		return "", time.Time{}, &AuthenticationError{Message: "failed to create request", Err: err}
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -111,37 +223,37 @@ func (tm *TokenManager) refreshToken() (string, error) {
 
 	resp, err := tm.client.Do(req)
 	if err != nil {
-		return "", &AuthenticationError{Message: "OAuth token request failed", Err: err}
+		return "", time.Time{}, &AuthenticationError{Message: "OAuth token request failed", Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", &AuthenticationError{
+		return "", time.Time{}, &AuthenticationError{
 			Message: fmt.Sprintf("OAuth request failed with status %d: %s", resp.StatusCode, string(body)),
 		}
 	}
 
 	var tokenResp TokenResponse
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return "", &AuthenticationError{Message: "failed to decode token response", Err: err}
+		return "", time.Time{}, &AuthenticationError{Message: "failed to decode token response", Err: err}
 	}
 
 	if tokenResp.AccessToken == "" {
-		return "", &AuthenticationError{Message: "invalid token response: empty access token"}
+		return "", time.Time{}, &AuthenticationError{Message: "invalid token response: empty access token"}
 	}
 
-	tm.accessToken = tokenResp.AccessToken
-
+	expiresAt := time.Now().Add(29 * time.Minute)
 	if tokenResp.ExpiresAt > 0 {
-		expiresAt := tokenResp.ExpiresAt
-		if expiresAt > 1000000000000 {
-			expiresAt = expiresAt / 1000
-		}
-		tm.expiresAt = time.Unix(expiresAt, 0)
-	} else {
-		tm.expiresAt = time.Now().Add(29 * time.Minute)
+		expiresAt = time.Unix(normalizeUnixTime(tokenResp.ExpiresAt), 0)
 	}
 
-	return tm.accessToken, nil
+	return tokenResp.AccessToken, expiresAt, nil
+}
+
+func normalizeUnixTime(value int64) int64 {
+	if value > 1000000000000 {
+		return value / 1000
+	}
+	return value
 }
@@ -0,0 +1,151 @@
+package gigachat
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+const (
+	minRefreshRetryDelay = 5 * time.Second
+	maxRefreshRetryDelay = time.Minute
+)
+
+func WithBackgroundRefresh(ahead time.Duration) TokenManagerOption {
+	return func(tm *TokenManager) {
+		tm.refreshAhead = ahead
+	}
+}
+
+func WithRefreshErrorHandler(handler func(error)) TokenManagerOption {
+	return func(tm *TokenManager) {
+		tm.onRefreshError = handler
+	}
+}
+
+func (tm *TokenManager) Stop() {
+	tm.stopOnce.Do(func() {
+		if tm.stopRefresh == nil {
+			return
+		}
+		tm.stopRefresh()
+		<-tm.refreshDone
+	})
+}
+
+func (tm *TokenManager) startBackgroundRefresh() {
+	ctx, cancel := context.WithCancel(context.Background())
+	tm.stopRefresh = cancel
+	tm.refreshDone = make(chan struct{})
+
+	go tm.backgroundRefreshLoop(ctx)
+}
+
+func (tm *TokenManager) backgroundRefreshLoop(ctx context.Context) {
+	defer close(tm.refreshDone)
+
+	failures := 0
+	delay := tm.nextRefreshDelay()
+
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		extended, err := tm.refreshInBackground(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if tm.onRefreshError != nil {
+				tm.onRefreshError(err)
+			}
+
+			failures++
+			delay = refreshRetryDelay(failures)
+			tm.logger.WarnContext(ctx, "gigachat background token refresh failed",
+				"failures", failures, "retry_in", delay, "error", err)
+			continue
+		}
+
+		if !extended {
+			failures++
+			delay = max(tm.nextRefreshDelay(), refreshRetryDelay(failures))
+			tm.logger.WarnContext(ctx, "gigachat background token refresh did not extend expiry",
+				"failures", failures, "retry_in", delay)
+			continue
+		}
+
+		failures = 0
+		delay = tm.nextRefreshDelay()
+	}
+}
+
+func (tm *TokenManager) nextRefreshDelay() time.Duration {
+	tm.mu.RLock()
+	token, expiresAt := tm.accessToken, tm.expiresAt
+	tm.mu.RUnlock()
+
+	if token == "" {
+		return 0
+	}
+
+	remaining := time.Until(expiresAt)
+	ahead := min(tm.refreshAhead, remaining/2)
+	if ahead < 0 {
+		ahead = 0
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(ahead)/4 + 1))
+	delay := remaining - ahead - jitter
+	if delay < minRefreshRetryDelay {
+		return minRefreshRetryDelay
+	}
+	return delay
+}
+
+func refreshRetryDelay(failures int) time.Duration {
+	delay := minRefreshRetryDelay << (failures - 1)
+	if delay > maxRefreshRetryDelay || delay <= 0 {
+		delay = maxRefreshRetryDelay
+	}
+	return delay
+}
+
+func (tm *TokenManager) refreshInBackground(ctx context.Context) (bool, error) {
+	tm.mu.RLock()
+	currentExpiry := tm.expiresAt
+	tm.mu.RUnlock()
+
+	if tm.store != nil {
+		token, expiresAt, err := tm.store.Get(ctx)
+		if err == nil && token != "" && expiresAt.After(currentExpiry) && time.Now().Add(tm.refreshAhead).Before(expiresAt) {
+			tm.setToken(token, expiresAt)
+			tm.logger.DebugContext(ctx, "gigachat access token loaded from store", "expires_at", expiresAt)
+			return true, nil
+		}
+	}
+
+	token, expiresAt, err := tm.fetchToken(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	tm.setToken(token, expiresAt)
+	tm.saveToStore(ctx, token, expiresAt)
+
+	return expiresAt.After(currentExpiry), nil
+}
+
+func (tm *TokenManager) setToken(token string, expiresAt time.Time) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.accessToken = token
+	tm.expiresAt = expiresAt
+	tm.invalidToken = ""
+}
@@ -0,0 +1,112 @@
+package gigachat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type TokenStore interface {
+	Get(ctx context.Context) (token string, expiresAt time.Time, err error)
+	Set(ctx context.Context, token string, expiresAt time.Time) error
+}
+
+type MemoryTokenStore struct {
+	mu        sync.RWMutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{}
+}
+
+func (s *MemoryTokenStore) Get(ctx context.Context) (string, time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.token, s.expiresAt, nil
+}
+
+func (s *MemoryTokenStore) Set(ctx context.Context, token string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+	s.expiresAt = expiresAt
+	return nil
+}
+
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+type storedToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+func (s *FileTokenStore) Get(ctx context.Context) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var stored storedToken
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return stored.AccessToken, time.UnixMilli(stored.ExpiresAt), nil
+}
+
+func (s *FileTokenStore) Set(ctx context.Context, token string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(storedToken{AccessToken: token, ExpiresAt: expiresAt.UnixMilli()})
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}